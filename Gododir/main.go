@@ -53,13 +53,17 @@ func tasks(p *do.Project) {
 	})
 
 	p.Task("exec_process", nil, func(c *do.Context) {
-		c.Run("ssh {{.edison_host}} 'nohup /home/{{.edison_user}}/{{.app_name}} > /home/{{.edison_user}}/output.log 2>&1 &'", options)
+		c.Run("ssh {{.edison_host}} 'nohup /home/{{.edison_user}}/{{.app_name}} -log /home/{{.edison_user}}/{{.app_name}}.log > /home/{{.edison_user}}/output.log 2>&1 &'", options)
 	})
 
 	p.Task("see_output", nil, func(c *do.Context) {
 		c.Run("ssh {{.edison_host}} 'tail -f output.log'", options)
 	})
 
+	p.Task("see_log", nil, func(c *do.Context) {
+		c.Run("ssh {{.edison_host}} 'tail -f {{.app_name}}.log'", options)
+	})
+
 	defaultTask := do.S{
 		"build",
 		"killall_process",
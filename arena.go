@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrarena"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+const arenaUsage = `Usage: MagicReversi arena [flags] <engine> <engine>...
+
+Engines:
+  search:depth=6,mobility=5,weights=weights.txt
+  mcts:playouts=10000,time=1s,parallel=1,exploration=1.41,biased=true
+  Options of all engines are solve=14, book=default or a file, and name=NAME.`
+
+// runArena runs the arena subcommand
+func runArena(args []string) error {
+	fs := flag.NewFlagSet("arena", flag.ExitOnError)
+
+	games := fs.Int("games", 100, "number of games of each pair of engines")
+	random := fs.Int("random", 4, "number of random moves of each opening")
+	parallel := fs.Int("parallel", runtime.NumCPU(), "number of games played at the same time")
+	seed := fs.Int64("seed", 0, "seed of random openings, the current time if 0")
+	start := fs.String("start", "standard 8", "position from which openings are made, as a board diagram or a transcript")
+
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, arenaUsage)
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		return errors.New(arenaUsage)
+	}
+
+	entrants := []mrarena.Entrant{}
+
+	for _, spec := range fs.Args() {
+		e, err := mrarena.ParseEntrant(spec)
+
+		if err != nil {
+			return err
+		}
+
+		entrants = append(entrants, e)
+	}
+
+	pos, err := mrsoft.ParsePosition(*start)
+
+	if err != nil {
+		return err
+	}
+
+	a := mrarena.NewArena(entrants...)
+	a.Games = *games
+	a.RandomMoves = *random
+	a.Parallel = *parallel
+	a.Seed = *seed
+	a.Start = pos
+
+	began := time.Now()
+	results, err := a.Run()
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%d games in %s\n\n", len(results), time.Since(began).Round(time.Second))
+	mrarena.Report(os.Stdout, entrants, results)
+
+	return nil
+}
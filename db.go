@@ -0,0 +1,255 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrdb"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+const dbUsage = `Usage: MagicReversi db <command> [flags]
+
+Commands:
+  list [flags]         list games matching the flags
+  show <id>            show the game record
+  export [flags]       print games matching the flags as transcripts or GGF
+  import <file>        add games of a GGF file or a WTHOR .wtb file
+  book <file>          add the openings of games matching the flags to the book file
+  register <id> <name> add a player profile
+  players              list player profiles
+  ratings              show the leaderboard of rated games
+  versus <id> <id>     show the rated results between two players`
+
+// runDB runs the db subcommand
+func runDB(args []string) error {
+	if len(args) == 0 {
+		return errors.New(dbUsage)
+	}
+
+	fs := flag.NewFlagSet("db "+args[0], flag.ExitOnError)
+
+	path := fs.String("db", "games.jsonl", "game database file")
+	player := fs.String("player", "", "name of either player")
+	result := fs.String("result", "", "result of the game (black, white or draw)")
+	from := fs.String("from", "", "date from which games started (2006-01-02)")
+	to := fs.String("to", "", "date until which games started (2006-01-02)")
+	opening := fs.String("opening", "", "name of the opening")
+	position := fs.String("position", "", "position reached in games as a board diagram or a transcript")
+	format := fs.String("format", "transcript", "export format (transcript or ggf)")
+	players := fs.String("players", "", "WTHOR players file (.JOU) to import")
+	tournaments := fs.String("tournaments", "", "WTHOR tournaments file (.TRN) to import")
+	profilesPath := fs.String("profiles", "profiles.json", "player profiles file")
+	depth := fs.Int("depth", 16, "number of moves of each game added to the book")
+
+	fs.Parse(args[1:])
+
+	db, err := mrdb.Open(*path)
+
+	if err != nil {
+		return err
+	}
+
+	q := mrdb.Query{Player: *player, Result: *result, Opening: *opening}
+
+	if q.From, err = parseDate(*from); err != nil {
+		return err
+	}
+
+	if q.To, err = parseDate(*to); err != nil {
+		return err
+	}
+
+	// the end date is inclusive
+	if !q.To.IsZero() {
+		q.To = q.To.AddDate(0, 0, 1)
+	}
+
+	if *position != "" {
+		pos, err := mrsoft.ParsePosition(*position)
+
+		if err != nil {
+			return err
+		}
+
+		q.Position = &pos
+	}
+
+	switch args[0] {
+	case "list":
+		for _, g := range db.Find(q) {
+			fmt.Printf("%d\t%s\t%s vs %s\t%s %d-%d\t%s\n", g.ID, g.Started.Format("2006-01-02 15:04"), g.Black, g.White, g.Result(), g.Record.Black, g.Record.White, g.Record.Opening)
+		}
+	case "show":
+		id, err := strconv.Atoi(fs.Arg(0))
+
+		if err != nil {
+			return fmt.Errorf("Invalid game ID: %q", fs.Arg(0))
+		}
+
+		g, ok := db.Get(id)
+
+		if !ok {
+			return fmt.Errorf("Game %d is not found", id)
+		}
+
+		fmt.Printf("BLACK:\t\t%s\nWHITE:\t\t%s\n", g.Black, g.White)
+		fmt.Printf("STARTED:\t%s\nFINISHED:\t%s\n", g.Started.Format(time.RFC3339), g.Finished.Format(time.RFC3339))
+
+		return g.Record.Write(os.Stdout)
+	case "export":
+		for _, g := range db.Find(q) {
+			switch *format {
+			case "transcript":
+				fmt.Println(g.Record.Transcript())
+			case "ggf":
+				if err = mrdb.WriteGGF(os.Stdout, g); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("Unknown format: %s", *format)
+			}
+		}
+	case "import":
+		games, err := importGames(fs.Arg(0), *players, *tournaments)
+
+		if err != nil {
+			return err
+		}
+
+		for _, g := range games {
+			if err = db.Add(g); err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("%d games are imported\n", len(games))
+	case "book":
+		if fs.NArg() != 1 {
+			return errors.New("Usage: MagicReversi db book [flags] <file>")
+		}
+
+		return buildBook(fs.Arg(0), db.Find(q), *depth)
+	case "register":
+		ps, err := mrdb.OpenProfiles(*profilesPath)
+
+		if err != nil {
+			return err
+		}
+
+		if fs.NArg() < 2 {
+			return errors.New("Usage: MagicReversi db register <id> <name>")
+		}
+
+		return ps.Add(mrdb.Profile{ID: fs.Arg(0), Name: strings.Join(fs.Args()[1:], " ")})
+	case "players":
+		ps, err := mrdb.OpenProfiles(*profilesPath)
+
+		if err != nil {
+			return err
+		}
+
+		for _, p := range ps.List() {
+			fmt.Printf("%s\t%.0f\t%s\n", p.ID, db.Rating(p.ID), p.Name)
+		}
+	case "ratings":
+		fmt.Printf("RANK\tPLAYER\tRATING\tGAMES\tWIN\tDRAW\tLOSS\n")
+
+		for i, s := range db.Leaderboard() {
+			fmt.Printf("%d\t%s\t%.0f\t%d\t%d\t%d\t%d\n", i+1, s.ID, s.Rating, s.Games, s.Wins, s.Draws, s.Losses)
+		}
+	case "versus":
+		if fs.NArg() != 2 {
+			return errors.New("Usage: MagicReversi db versus <id> <id>")
+		}
+
+		a, b := fs.Arg(0), fs.Arg(1)
+		s := db.HeadToHead(a, b)
+
+		fmt.Printf("%s (%.0f) VS %s (%.0f)\n", a, db.Rating(a), b, db.Rating(b))
+		fmt.Printf("%d GAMES:\t%d WIN, %d DRAW, %d LOSS\n", s.Games, s.Wins, s.Draws, s.Losses)
+		fmt.Printf("EXPECTED SCORE:\t%.2f\n", mrdb.Expected(db.Rating(a), db.Rating(b)))
+	default:
+		return errors.New(dbUsage)
+	}
+
+	return nil
+}
+
+// importGames reads games of the GGF or WTHOR file at path
+func importGames(path, players, tournaments string) ([]*mrdb.Game, error) {
+	if strings.EqualFold(filepath.Ext(path), ".wtb") {
+		return mrdb.LoadWTHOR(path, players, tournaments)
+	}
+
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return mrdb.ReadGGF(f)
+}
+
+// buildBook adds the first depth moves of the games to the book file at path,
+// which is created if it doesn't exist. Games not from the normal beginning of 8x8 standard rules are skipped.
+func buildBook(path string, games []*mrdb.Game, depth int) error {
+	b, err := mrsoft.LoadBook(path)
+
+	if os.IsNotExist(err) {
+		b, err = mrsoft.NewBook(), nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	n := 0
+
+	for _, g := range games {
+		r := g.Record
+
+		if r.Size != 8 || r.Rules != mrsoft.STANDARD || r.Start != "" {
+			continue
+		}
+
+		if err = b.AddGame(r.Moves, depth); err != nil {
+			return fmt.Errorf("Game %d: %s", g.ID, err)
+		}
+
+		n++
+	}
+
+	f, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	if err = b.Write(f); err != nil {
+		return err
+	}
+
+	fmt.Printf("%d games are added to %s\n", n, path)
+
+	return nil
+}
+
+// parseDate parses a date such as 2006-01-02, empty for zero time
+func parseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	return time.ParseInLocation("2006-01-02", s, time.Local)
+}
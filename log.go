@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
+	lumberjack "gopkg.in/natefinch/lumberjack.v2"
+)
+
+// logger is the structured logger of the board and the Game
+var logger = logrus.New()
+
+// setupLogger configures the logger by flags
+func setupLogger() error {
+	lv, err := logrus.ParseLevel(*logLevel)
+
+	if err != nil {
+		return err
+	}
+
+	logger.SetLevel(lv)
+
+	switch *logFormat {
+	case "logfmt":
+		logger.SetFormatter(&logrus.TextFormatter{DisableColors: true, FullTimestamp: true})
+	case "json":
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	default:
+		return fmt.Errorf("Unknown log format: %s", *logFormat)
+	}
+
+	if *logPath != "" {
+		logger.SetOutput(&lumberjack.Logger{
+			Filename:   *logPath,
+			MaxSize:    *logSize,
+			MaxBackups: *logBackups,
+			Compress:   true,
+		})
+	}
+
+	return nil
+}
+
+// watchLogLevel makes the log more verbose by SIGUSR1 and less verbose by SIGUSR2
+func watchLogLevel() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, syscall.SIGUSR1, syscall.SIGUSR2)
+
+	for s := range c {
+		lv := logger.GetLevel()
+
+		if s == syscall.SIGUSR1 && lv < logrus.TraceLevel {
+			lv++
+		}
+
+		if s == syscall.SIGUSR2 && lv > logrus.PanicLevel {
+			lv--
+		}
+
+		setLogLevel(lv)
+	}
+}
+
+// setLogLevel changes the verbosity of the logger
+func setLogLevel(lv logrus.Level) {
+	logger.SetLevel(lv)
+	logger.WithField("level", lv.String()).Warn("Log level changed")
+}
+
+// fatal prints err and exits. err is also logged when the log goes to a file,
+// otherwise the logger would print it to stderr twice.
+func fatal(err error) {
+	if *logPath != "" {
+		logger.WithError(err).Error("Exit by error")
+	}
+
+	log.Fatal(err)
+}
@@ -1,27 +1,278 @@
 package main
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
 	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/69guitar1015/MagicReversi/mrnboard"
 	"github.com/69guitar1015/MagicReversi/mrsoft"
+	"github.com/sirupsen/logrus"
 )
 
+var (
+	cpu      = flag.String("cpu", "", "color played by the computer (black or white)")
+	playouts = flag.Int("playouts", 10000, "number of playouts of the computer per move")
+	think    = flag.Duration("think", 0, "thinking time of the computer per move, overrides -playouts")
+	solve    = flag.Int("solve", 14, "number of blank cells from which the computer plays perfectly")
+	bookPath = flag.String("book", "", "opening book file, the built-in book is used if empty")
+	analyze  = flag.Bool("analyze", false, "analyze the game after it finishes")
+	rules    = flag.String("rules", "standard", "rules of the game (standard or anti)")
+	savePath = flag.String("save", "", "file to save the game record")
+	start    = flag.String("start", "", "starting position as a board diagram or a transcript")
+	turn     = flag.String("turn", "", "color to move first (black or white), as the starting position if empty")
+	handicap = flag.Int("handicap", 0, "number of corners given to the weaker player")
+	weaker   = flag.String("weaker", "black", "color of the weaker player who gets the handicap")
+	mainTime = flag.Duration("time", 0, "main time of each player, no clock if both -time and -byoyomi are 0")
+	inc      = flag.Duration("increment", 0, "time added after every move")
+	byoyomi  = flag.Duration("byoyomi", 0, "time for each move after the main time runs out")
+	resume   = flag.String("resume", "", "game record file saved by -save to continue")
+	dbPath   = flag.String("db", "games.jsonl", "game database file to store finished games, disabled if empty")
+	black    = flag.String("black", "", "name of the black player")
+	white    = flag.String("white", "", "name of the white player")
+	profiles = flag.String("profiles", "profiles.json", "player profiles file, games between two profiles are rated")
+	external = flag.String("engine", "", "command of an external NBoard engine such as \"edax -nboard\" played by the computer")
+	level    = flag.Int("level", 10, "search depth of the external engine")
+	nboard   = flag.String("nboard", "", "serve the NBoard protocol to a GUI on stdin and stdout by \"-\" or on a TCP address such as \":5000\"")
+	metrics  = flag.String("metrics", "", "TCP address such as \":9100\" to serve Prometheus metrics at /metrics, disabled if empty")
+
+	mqttBroker = flag.String("mqtt", "", "MQTT broker such as \"tcp://localhost:1883\" to publish the game to, disabled if empty")
+	mqttTopic  = flag.String("mqtt-topic", "magicreversi", "prefix of the MQTT topics")
+	mqttID     = flag.String("mqtt-id", "magicreversi", "MQTT client ID")
+
+	logPath    = flag.String("log", "", "log file rotated by size, stderr if empty")
+	logFormat  = flag.String("log-format", "logfmt", "format of the log (logfmt or json)")
+	logLevel   = flag.String("log-level", "info", "verbosity of the log (error, warn, info, debug or trace), changed by SIGUSR1 and SIGUSR2 while running")
+	logSize    = flag.Int("log-size", 10, "size in megabytes at which the log file is rotated")
+	logBackups = flag.Int("log-backups", 5, "number of rotated log files kept")
+)
+
+// stdin is shared by prompts and commands typed on the terminal
+var stdin = bufio.NewScanner(os.Stdin)
+
+// book is the opening book of the computer
+var book = mrsoft.DefaultBook()
+
 func checkError(err error, m *mrmiddle.MrMiddle) {
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
+	}
+}
+
+// parseColor returns the Player of the color name
+func parseColor(s string) (mrsoft.Player, error) {
+	switch s {
+	case "black":
+		return mrsoft.BLACK, nil
+	case "white":
+		return mrsoft.WHITE, nil
+	default:
+		return mrsoft.NONE, fmt.Errorf("Unknown color: %s", s)
+	}
+}
+
+// gameOptions returns the Options of the Game configured by flags
+func gameOptions() (opts []mrsoft.Option, err error) {
+	r, err := mrsoft.ParseRuleSet(*rules)
+
+	if err != nil {
+		return
+	}
+
+	opts = append(opts, mrsoft.Rules(r))
+
+	if *start != "" {
+		pos, err := mrsoft.ParsePosition(*start)
+
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, mrsoft.Size(pos.Size()), mrsoft.StartFrom(pos))
+	}
+
+	if *handicap != 0 {
+		pl, err := parseColor(*weaker)
+
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, mrsoft.Handicap(*handicap, pl))
+	}
+
+	if *mainTime != 0 || *byoyomi != 0 {
+		opts = append(opts, mrsoft.Timed(mrsoft.TimeControl{Main: *mainTime, Increment: *inc, Byoyomi: *byoyomi}))
+	}
+
+	if *turn != "" {
+		pl, err := parseColor(*turn)
+
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, mrsoft.Turn(pl))
+	}
+
+	if *resume != "" {
+		r, err := loadRecord(*resume)
+
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, mrsoft.Resume(r))
+	}
+
+	return
+}
+
+// newEngine returns the Engine configured by flags
+func newEngine() mrsoft.Engine {
+	e := mrsoft.NewMCTS()
+	e.Playouts = *playouts
+	e.TimeLimit = *think
+
+	eg := mrsoft.NewEndgame(e)
+	eg.Solver.Empties = *solve
+
+	if *external == "" {
+		return mrsoft.NewBookEngine(book, eg)
+	}
+
+	// the own engine plays when the external one fails
+	x := mrnboard.NewEngine(strings.Fields(*external)...)
+	x.Depth = *level
+	x.Fallback = eg
+
+	if *think > 0 {
+		x.TimeLimit = *think
+	}
+
+	return x
+}
+
+// readCommands sends commands typed on the terminal to the middleware
+func readCommands(m *mrmiddle.MrMiddle) {
+	for stdin.Scan() {
+		switch strings.TrimSpace(stdin.Text()) {
+		case "hint":
+			m.Send(mrmiddle.HINT)
+		case "undo":
+			m.Send(mrmiddle.UNDO)
+		case "redo":
+			m.Send(mrmiddle.REDO)
+		case "pause":
+			m.Send(mrmiddle.PAUSE)
+		case "resume":
+			m.Send(mrmiddle.RESUME)
+		case "new":
+			m.Send(mrmiddle.NEWGAME)
+		case "":
+		default:
+			fields := strings.Fields(stdin.Text())
+
+			if len(fields) == 2 && fields[0] == "log" {
+				if lv, err := logrus.ParseLevel(fields[1]); err == nil {
+					setLogLevel(lv)
+					continue
+				}
+			}
+
+			fmt.Println("Commands: hint, undo, redo, pause, resume, new, log LEVEL")
+		}
+	}
+}
+
+// playerName returns the name of the color given by flags
+func playerName(name, color string) string {
+	switch {
+	case name != "":
+		return name
+	case *cpu == color:
+		return "computer"
+	default:
+		return "human"
 	}
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "db" {
+		if err := runDB(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "arena" {
+		if err := runArena(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "perft" {
+		if err := runPerft(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+
+		return
+	}
+
+	flag.Parse()
+
+	if err := setupLogger(); err != nil {
+		log.Fatal(err)
+	}
+
+	go watchLogLevel()
+
+	if *bookPath != "" {
+		b, err := mrsoft.LoadBook(*bookPath)
+
+		if err != nil {
+			fatal(err)
+		}
+
+		book = b
+	}
+
+	if *metrics != "" {
+		if err := serveMetrics(*metrics); err != nil {
+			fatal(err)
+		}
+	}
+
+	// stdin is used by the GUI
+	stdio := *nboard == "-"
+
+	players := gamePlayers{}
+
+	if !stdio {
+		p, err := choosePlayers()
+
+		if err != nil {
+			fatal(err)
+		}
+
+		players = p
+	}
+
 	m, err := mrmiddle.NewMrMiddle()
 
 	checkError(err, m)
 
+	m.SetLogger(logger)
+
 	defer m.Finalize()
 
 	signalChan := make(chan os.Signal, 1)
@@ -42,9 +293,99 @@ func main() {
 
 	checkError(err, m)
 
-	g := mrsoft.NewGame(m)
+	if !stdio {
+		go readCommands(m)
+	}
+
+	opts, err := gameOptions()
+
+	checkError(err, m)
+
+	g, err := mrsoft.NewGame(m, opts...)
+
+	checkError(err, m)
+
+	g.SetLogger(logger)
+
+	g.SetBook(book)
+
+	if *analyze {
+		g.SetReviewer(mrsoft.NewReviewer())
+	}
+
+	if *cpu != "" {
+		pl, err := parseColor(*cpu)
 
-	err = g.Start()
+		checkError(err, m)
+
+		g.SetEngine(pl, newEngine())
+	}
+
+	if *mqttBroker != "" {
+		c, err := connectMQTT(g, m)
+
+		checkError(err, m)
+
+		defer c.Disconnect()
+	}
+
+	if *dbPath != "" {
+		g.SetRater(&dbRater{path: *dbPath, players: players})
+	}
+
+	if *nboard != "" {
+		checkError(serveNBoard(g, *nboard), m)
+		return
+	}
+
+	for {
+		err = g.Start()
+
+		if *savePath != "" {
+			if e := saveRecord(g.Record(), *savePath); e != nil {
+				logger.WithError(e).Error("Failed to save the record")
+			}
+		}
+
+		// another game is started by the command from MQTT
+		if err != nil || *mqttBroker == "" {
+			break
+		}
+
+		if err = waitNewGame(m); err != nil {
+			break
+		}
+
+		if err = g.Restart(); err != nil {
+			break
+		}
+	}
 
 	checkError(err, m)
 }
+
+// loadRecord reads the game record at path
+func loadRecord(path string) (*mrsoft.Record, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return mrsoft.ReadRecord(f)
+}
+
+// saveRecord writes the game record to path
+func saveRecord(r *mrsoft.Record, path string) error {
+	f, err := os.Create(path)
+
+	if err != nil {
+		return err
+	}
+
+	defer f.Close()
+
+	return r.Write(f)
+}
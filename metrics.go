@@ -0,0 +1,32 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// serveMetrics serves the metrics of the board and games at /metrics on addr in background
+func serveMetrics(addr string) error {
+	r := prometheus.NewRegistry()
+
+	if err := mrmiddle.RegisterMetrics(r); err != nil {
+		return err
+	}
+
+	if err := mrsoft.RegisterMetrics(r); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(r, promhttp.HandlerOpts{}))
+
+	go func() {
+		logger.WithError(http.ListenAndServe(addr, mux)).Error("Metrics server stopped")
+	}()
+
+	return nil
+}
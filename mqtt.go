@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/69guitar1015/MagicReversi/mrmqtt"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// connectMQTT publishes the events of the Game to the broker given by flags
+// and sends the commands from the broker to the board
+func connectMQTT(g *mrsoft.Game, m *mrmiddle.MrMiddle) (mrmqtt.Client, error) {
+	c, err := mrmqtt.Dial(*mqttBroker, *mqttID, logger)
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to connect to MQTT broker: %s", err)
+	}
+
+	b := mrmqtt.NewBridge(c, mrmqtt.NewTopics(*mqttTopic), m)
+	b.SetLogger(logger)
+
+	if err = b.Listen(); err != nil {
+		c.Disconnect()
+		return nil, fmt.Errorf("Failed to subscribe to MQTT commands: %s", err)
+	}
+
+	g.SetObserver(b)
+
+	return c, nil
+}
+
+// waitNewGame waits until a new game is requested, ignoring the stones moved on the board
+func waitNewGame(m *mrmiddle.MrMiddle) error {
+	fmt.Println("Waiting for a new game...")
+
+	for {
+		x, _, err := m.GetInput()
+
+		if err != nil {
+			return err
+		}
+
+		if x == int(mrmiddle.NEWGAME) {
+			return nil
+		}
+	}
+}
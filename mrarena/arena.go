@@ -0,0 +1,189 @@
+package mrarena
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// Arena plays games between every pair of Entrants on a simulated board.
+// Each opening is played twice by a pair with the colors swapped.
+type Arena struct {
+	Entrants []Entrant
+	// Games is the number of games of each pair, rounded up to an even number
+	Games int
+	// RandomMoves is the number of random moves of the opening
+	RandomMoves int
+	// Start is the position from which openings are made
+	Start mrsoft.Position
+	// Parallel is the number of games played at the same time
+	Parallel int
+	// Seed is the seed of random openings, 0 means the current time
+	Seed int64
+}
+
+// NewArena returns an Arena of the Entrants with default settings
+func NewArena(entrants ...Entrant) *Arena {
+	return &Arena{
+		Entrants:    entrants,
+		Games:       100,
+		RandomMoves: 4,
+		Start:       mrsoft.InitialPosition(),
+		Parallel:    runtime.NumCPU(),
+	}
+}
+
+// Result is a finished game of the Arena
+type Result struct {
+	// Black and White are the indexes of Entrants
+	Black, White int
+	// Moves are all moves of the game including the random opening
+	Moves []mrsoft.Point
+	// Opening is the number of random moves at the beginning of Moves
+	Opening int
+	Winner  mrsoft.Player
+	// Diff is the disc differential for black, negated by ANTI rules
+	Diff int
+}
+
+// Run plays all games and returns the results in the order of the schedule
+func (a *Arena) Run() ([]Result, error) {
+	if len(a.Entrants) < 2 {
+		return nil, errors.New("The arena needs 2 or more engines")
+	}
+
+	seed := a.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	results := []Result{}
+
+	// all pairs play the same openings
+	for k := 0; k < (a.Games+1)/2; k++ {
+		opening := a.opening(rand.New(rand.NewSource(seed + int64(k))))
+
+		for i := range a.Entrants {
+			for j := i + 1; j < len(a.Entrants); j++ {
+				results = append(results,
+					Result{Black: i, White: j, Moves: opening, Opening: len(opening)},
+					Result{Black: j, White: i, Moves: opening, Opening: len(opening)})
+			}
+		}
+	}
+
+	parallel := a.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	jobs := make(chan int)
+	errs := make(chan error, len(results))
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				if err := a.play(&results[i]); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	for i := range results {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+	close(errs)
+
+	if err := <-errs; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// opening returns random moves from Start
+func (a *Arena) opening(r *rand.Rand) (moves []mrsoft.Point) {
+	pos := a.Start
+
+	for len(moves) < a.RandomMoves && !pos.IsFinish() {
+		available := pos.Moves()
+
+		// passes are left to Replay
+		if len(available) == 0 {
+			pos.Replay([]mrsoft.Point{{}})
+			continue
+		}
+
+		m := available[r.Intn(len(available))]
+		pos.Replay([]mrsoft.Point{m})
+		moves = append(moves, m)
+	}
+
+	return
+}
+
+// play plays the game of res from its opening to the end
+func (a *Arena) play(res *Result) error {
+	engines := map[mrsoft.Player]mrsoft.Engine{
+		mrsoft.BLACK: a.Entrants[res.Black].New(),
+		mrsoft.WHITE: a.Entrants[res.White].New(),
+	}
+
+	names := map[mrsoft.Player]string{
+		mrsoft.BLACK: a.Entrants[res.Black].Name,
+		mrsoft.WHITE: a.Entrants[res.White].Name,
+	}
+
+	pos := a.Start
+	moves := append([]mrsoft.Point{}, res.Moves...)
+
+	if err := pos.Replay(moves); err != nil {
+		return err
+	}
+
+	for !pos.IsFinish() {
+		if len(pos.Moves()) == 0 {
+			pos.Replay([]mrsoft.Point{{}})
+			continue
+		}
+
+		turn := pos.Turn()
+		m, err := engines[turn].Move(pos)
+
+		if err != nil {
+			return fmt.Errorf("%s as %s: %s", names[turn], turn, err)
+		}
+
+		if err = pos.Replay([]mrsoft.Point{m}); err != nil {
+			return fmt.Errorf("%s as %s: %s", names[turn], turn, err)
+		}
+
+		moves = append(moves, m)
+	}
+
+	black, white, _ := pos.Count()
+
+	res.Moves = moves
+	res.Winner = pos.Winner()
+	res.Diff = black - white
+
+	if pos.Rules() == mrsoft.ANTI {
+		res.Diff = -res.Diff
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package mrarena
+
+import (
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+func TestArena(t *testing.T) {
+	entrants := []Entrant{}
+
+	for _, spec := range []string{"search:depth=1", "search:depth=3,mobility=0", "mcts:playouts=50,name=mcts"} {
+		e, err := ParseEntrant(spec)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		entrants = append(entrants, e)
+	}
+
+	start, err := mrsoft.NewPosition(6)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewArena(entrants...)
+	a.Games = 3
+	a.Start = start
+	a.Seed = 1
+
+	results, err := a.Run()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 4 games of each of 3 pairs
+	if len(results) != 12 {
+		t.Fatalf("Run returns %d results, want 12", len(results))
+	}
+
+	for i := 0; i < len(results); i += 2 {
+		r, s := results[i], results[i+1]
+
+		if r.Black != s.White || r.White != s.Black {
+			t.Fatalf("Colors are not swapped: %+v and %+v", r, s)
+		}
+
+		for k := 0; k < a.RandomMoves; k++ {
+			if r.Moves[k] != s.Moves[k] {
+				t.Fatalf("Openings differ: %v and %v", r.Moves, s.Moves)
+			}
+		}
+
+		pos := start
+		if err := pos.Replay(r.Moves); err != nil || !pos.IsFinish() {
+			t.Fatalf("%v is not a finished game: %v", r.Moves, err)
+		}
+	}
+
+	for i := range entrants {
+		s := Tally(results, i, -1)
+
+		if s.Games != 8 || s.Wins+s.Draws+s.Losses != 8 {
+			t.Fatalf("Tally of %s is %+v", entrants[i].Name, s)
+		}
+	}
+
+	if entrants[2].Name != "mcts" {
+		t.Fatalf("Name is %q", entrants[2].Name)
+	}
+}
+
+func TestParseEntrant(t *testing.T) {
+	for _, spec := range []string{"alphabeta", "search:depth", "search:playouts=10", "mcts:time=fast"} {
+		if _, err := ParseEntrant(spec); err == nil {
+			t.Fatalf("ParseEntrant(%q) returns no error", spec)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package mrarena
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// Entrant is an Engine taking part in the Arena
+type Entrant struct {
+	Name string
+	// New returns a new Engine for every game
+	// because engines are not safe for concurrent use
+	New func() mrsoft.Engine
+}
+
+// ParseEntrant parses the spec of an Entrant such as
+// "search:depth=6,mobility=3" or "mcts:playouts=2000,solve=12".
+//
+// Kinds are "search" (options depth, mobility, weights) and
+// "mcts" (options playouts, time, parallel, exploration, biased).
+// Options of all kinds are solve, book and name.
+// weights is a file of 64 numbers and book is a book file or "default".
+func ParseEntrant(spec string) (Entrant, error) {
+	kind, opts := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		kind, opts = spec[:i], spec[i+1:]
+	}
+
+	e := Entrant{Name: spec}
+
+	var (
+		s     *mrsoft.Searcher
+		m     *mrsoft.MCTS
+		solve int
+		book  *mrsoft.Book
+	)
+
+	switch kind {
+	case "search":
+		s = mrsoft.NewSearcher()
+	case "mcts":
+		// games run in parallel instead of the tree search
+		m = mrsoft.NewMCTS()
+		m.Parallel = 1
+	default:
+		return e, fmt.Errorf("Unknown engine: %q", kind)
+	}
+
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "" {
+			continue
+		}
+
+		kv := strings.SplitN(opt, "=", 2)
+
+		if len(kv) != 2 {
+			return e, fmt.Errorf("Invalid option of %s: %q", spec, opt)
+		}
+
+		var err error
+
+		switch key, value := kv[0], kv[1]; {
+		case key == "name":
+			e.Name = value
+		case key == "solve":
+			solve, err = strconv.Atoi(value)
+		case key == "book" && value == "default":
+			book = mrsoft.DefaultBook()
+		case key == "book":
+			book, err = mrsoft.LoadBook(value)
+		case s != nil && key == "depth":
+			s.Depth, err = strconv.Atoi(value)
+		case s != nil && key == "mobility":
+			s.Mobility, err = strconv.Atoi(value)
+		case s != nil && key == "weights":
+			s.Weights, err = loadWeights(value)
+		case m != nil && key == "playouts":
+			m.Playouts, err = strconv.Atoi(value)
+		case m != nil && key == "time":
+			m.TimeLimit, err = time.ParseDuration(value)
+		case m != nil && key == "parallel":
+			m.Parallel, err = strconv.Atoi(value)
+		case m != nil && key == "exploration":
+			m.Exploration, err = strconv.ParseFloat(value, 64)
+		case m != nil && key == "biased":
+			m.Biased, err = strconv.ParseBool(value)
+		default:
+			return e, fmt.Errorf("Unknown option of %s: %q", kind, key)
+		}
+
+		if err != nil {
+			return e, fmt.Errorf("Invalid option of %s: %s", spec, err)
+		}
+	}
+
+	e.New = func() mrsoft.Engine {
+		var engine mrsoft.Engine
+
+		if s != nil {
+			c := *s
+			engine = &c
+		} else {
+			c := *m
+			engine = &c
+		}
+
+		if solve > 0 {
+			eg := mrsoft.NewEndgame(engine)
+			eg.Solver.Empties = solve
+			engine = eg
+		}
+
+		if book != nil {
+			engine = mrsoft.NewBookEngine(book, engine)
+		}
+
+		return engine
+	}
+
+	return e, nil
+}
+
+// loadWeights reads 64 weights of 8 x 8 board row by row
+func loadWeights(path string) (w [8][8]int, err error) {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return
+	}
+
+	fields := strings.Fields(string(data))
+
+	if len(fields) != 64 {
+		return w, fmt.Errorf("%s has %d weights, not 64", path, len(fields))
+	}
+
+	for i, f := range fields {
+		if w[i/8][i%8], err = strconv.Atoi(f); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,117 @@
+package mrarena
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// z95 is the quantile of the normal distribution for 95% confidence
+const z95 = 1.96
+
+// Score is the results of an Entrant
+type Score struct {
+	Games, Wins, Draws, Losses int
+	// Discs is the sum of disc differentials from the view of the Entrant
+	Discs int
+}
+
+// Tally returns the Score of the i-th Entrant against the j-th one,
+// or against all others when j is negative
+func Tally(results []Result, i, j int) (s Score) {
+	for _, r := range results {
+		var color mrsoft.Player
+
+		switch {
+		case r.Black == i && (j < 0 || r.White == j):
+			color = mrsoft.BLACK
+		case r.White == i && (j < 0 || r.Black == j):
+			color = mrsoft.WHITE
+		default:
+			continue
+		}
+
+		s.Games++
+		s.Discs += r.Diff * int(color)
+
+		switch r.Winner {
+		case color:
+			s.Wins++
+		case mrsoft.NONE:
+			s.Draws++
+		default:
+			s.Losses++
+		}
+	}
+
+	return
+}
+
+// Rate returns the score per game where a draw is a half win
+func (s Score) Rate() float64 {
+	if s.Games == 0 {
+		return 0.5
+	}
+
+	return (float64(s.Wins) + float64(s.Draws)/2) / float64(s.Games)
+}
+
+// Elo returns the Elo rating difference against the opponents
+// and its 95% confidence interval [low, high].
+// They are infinite when the Entrant won or lost all games.
+func (s Score) Elo() (elo, low, high float64) {
+	rate := s.Rate()
+
+	// the standard error of the mean score per game
+	se := 0.0
+	if s.Games != 0 {
+		n := float64(s.Games)
+		v := (float64(s.Wins)*math.Pow(1-rate, 2) +
+			float64(s.Draws)*math.Pow(0.5-rate, 2) +
+			float64(s.Losses)*math.Pow(rate, 2)) / n
+		se = math.Sqrt(v / n)
+	}
+
+	return Elo(rate), Elo(math.Max(rate-z95*se, 0)), Elo(math.Min(rate+z95*se, 1))
+}
+
+// Elo returns the Elo rating difference expected from the score per game
+func Elo(rate float64) float64 {
+	return -400 * math.Log10(1/rate-1)
+}
+
+// Report writes the Score and the Elo rating of each Entrant against all others,
+// followed by the results of each pair when there are more than 2 Entrants
+func Report(w io.Writer, entrants []Entrant, results []Result) {
+	fmt.Fprintf(w, "%-32s %6s %5s %5s %5s %7s %6s  %s\n", "ENGINE", "GAMES", "WIN", "DRAW", "LOSS", "DISCS", "ELO", "95% CI")
+
+	for i, e := range entrants {
+		writeScore(w, e.Name, Tally(results, i, -1))
+	}
+
+	if len(entrants) <= 2 {
+		return
+	}
+
+	fmt.Fprintln(w, "\nHEAD TO HEAD")
+
+	for i := range entrants {
+		for j := i + 1; j < len(entrants); j++ {
+			writeScore(w, entrants[i].Name+" vs "+entrants[j].Name, Tally(results, i, j))
+		}
+	}
+}
+
+// writeScore writes a row of Report
+func writeScore(w io.Writer, name string, s Score) {
+	elo, low, high := s.Elo()
+
+	discs := 0.0
+	if s.Games != 0 {
+		discs = float64(s.Discs) / float64(s.Games)
+	}
+
+	fmt.Fprintf(w, "%-32s %6d %5d %5d %5d %+7.1f %+6.0f  [%+.0f, %+.0f]\n", name, s.Games, s.Wins, s.Draws, s.Losses, discs, elo, low, high)
+}
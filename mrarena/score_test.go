@@ -0,0 +1,45 @@
+package mrarena
+
+import (
+	"math"
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+func TestTally(t *testing.T) {
+	results := []Result{
+		{Black: 0, White: 1, Winner: mrsoft.BLACK, Diff: 10},
+		{Black: 1, White: 0, Winner: mrsoft.BLACK, Diff: 4},
+		{Black: 0, White: 2, Winner: mrsoft.NONE, Diff: 0},
+	}
+
+	if s := Tally(results, 0, -1); s != (Score{Games: 3, Wins: 1, Draws: 1, Losses: 1, Discs: 6}) {
+		t.Fatalf("Tally(0, -1) returns %+v", s)
+	}
+
+	if s := Tally(results, 1, 0); s != (Score{Games: 2, Wins: 1, Losses: 1, Discs: -6}) {
+		t.Fatalf("Tally(1, 0) returns %+v", s)
+	}
+}
+
+func TestElo(t *testing.T) {
+	if e := Elo(0.5); e != 0 {
+		t.Fatalf("Elo(0.5) is %f", e)
+	}
+
+	// 3:1 is about 191 points
+	if e := Elo(0.75); math.Abs(e-190.85) > 0.01 {
+		t.Fatalf("Elo(0.75) is %f", e)
+	}
+
+	elo, low, high := Score{Games: 100, Wins: 60, Draws: 10, Losses: 30}.Elo()
+
+	if !(low < elo && elo < high) || math.Abs(elo-Elo(0.65)) > 1e-9 {
+		t.Fatalf("Elo is %f [%f, %f]", elo, low, high)
+	}
+
+	if elo, _, high := (Score{Games: 4, Wins: 4}).Elo(); !math.IsInf(elo, 1) || !math.IsInf(high, 1) {
+		t.Fatalf("Elo of all wins is %f, %f", elo, high)
+	}
+}
@@ -0,0 +1,252 @@
+package mrdb
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// ggfDate is the layout of DT property of GGF
+const ggfDate = "2006.01.02_15:04:05"
+
+// ReadGGF reads all games "(;GM[Othello]...;)" of GGF (Generic Game Format)
+// and validates them by replaying through mrsoft.Game
+func ReadGGF(r io.Reader) (games []*Game, err error) {
+	br := bufio.NewReader(r)
+
+	for n := 1; ; n++ {
+		props, err := readGGFGame(br)
+
+		if err == io.EOF {
+			return games, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("GGF game %d: %s", n, err)
+		}
+
+		g, err := ggfGame(props)
+
+		if err != nil {
+			return nil, fmt.Errorf("GGF game %d: %s", n, err)
+		}
+
+		games = append(games, g)
+	}
+}
+
+// ggfProp is a property such as B[f5]
+type ggfProp struct {
+	key, value string
+}
+
+// readGGFGame reads properties between "(;" and ";)"
+func readGGFGame(r *bufio.Reader) (props []ggfProp, err error) {
+	// skip to the beginning of the game
+	for {
+		if _, err = r.ReadString('('); err != nil {
+			return
+		}
+
+		if c, err := r.ReadByte(); err != nil {
+			return nil, err
+		} else if c == ';' {
+			break
+		}
+	}
+
+	key := []byte{}
+
+	for {
+		c, err := r.ReadByte()
+
+		if err == io.EOF {
+			return nil, errors.New("Unexpected end of game")
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case c == ';' && len(key) == 0:
+			if c, err = r.ReadByte(); err != nil || c != ')' {
+				return nil, errors.New("Game doesn't end with \";)\"")
+			}
+
+			return props, nil
+		case c == '[':
+			value, err := r.ReadString(']')
+
+			if err != nil {
+				return nil, fmt.Errorf("Property %s is not closed", key)
+			}
+
+			props = append(props, ggfProp{string(key), strings.TrimSuffix(value, "]")})
+			key = key[:0]
+		case 'A' <= c && c <= 'Z':
+			key = append(key, c)
+		}
+	}
+}
+
+// ggfGame makes the Game of GGF properties
+func ggfGame(props []ggfProp) (*Game, error) {
+	g := &Game{Record: &mrsoft.Record{Size: 8, Rules: mrsoft.STANDARD}}
+	r := g.Record
+
+	for _, p := range props {
+		switch p.key {
+		case "GM":
+			if !strings.EqualFold(p.value, "Othello") {
+				return nil, fmt.Errorf("Unsupported game: %s", p.value)
+			}
+		case "PC":
+			g.Event = p.value
+		case "DT":
+			// the time zone such as ".MST" is ignored
+			if len(p.value) >= len(ggfDate) {
+				g.Started, _ = time.Parse(ggfDate, p.value[:len(ggfDate)])
+			}
+		case "PB":
+			g.Black = p.value
+		case "PW":
+			g.White = p.value
+		case "RE":
+			// the score for black may be followed by ":r" on resignation or ":t" on time such as "-12.00:r".
+			// "?" is the unknown result.
+			if v, err := strconv.ParseFloat(strings.SplitN(p.value, ":", 2)[0], 64); err == nil {
+				score := int(math.Round(v))
+				g.Score = &score
+			}
+
+			// the winner on time has the positive score such as "+64.00:t" for black
+			if strings.HasSuffix(p.value, ":t") {
+				r.TimeLoss = mrsoft.WHITE
+
+				if strings.HasPrefix(p.value, "-") {
+					r.TimeLoss = mrsoft.BLACK
+				}
+			}
+		case "TY":
+			// "8a" is the size followed by the variant such as anti
+			variant := strings.TrimLeft(p.value, "0123456789")
+			size, err := strconv.Atoi(strings.TrimSuffix(p.value, variant))
+
+			if err != nil {
+				return nil, fmt.Errorf("Invalid type: %s", p.value)
+			}
+
+			r.Size = size
+
+			if strings.Contains(variant, "a") {
+				r.Rules = mrsoft.ANTI
+			}
+		case "BO":
+			fields := strings.Fields(p.value)
+
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("Invalid board: %s", p.value)
+			}
+
+			pos, err := mrsoft.ParseBoard(strings.Join(fields[1:], ""))
+
+			if err != nil {
+				return nil, err
+			}
+
+			if !isInitial(&pos) {
+				r.Start = pos.Diagram()
+			}
+		case "B", "W":
+			// a move may be followed by the evaluation and the time such as "f5/1.00/0.01"
+			m, err := mrsoft.ParsePoint(strings.SplitN(p.value, "/", 2)[0])
+
+			if err != nil {
+				return nil, err
+			}
+
+			r.Moves = append(r.Moves, m)
+		}
+	}
+
+	return g, validate(g)
+}
+
+// WriteGGF writes the Game in GGF
+func WriteGGF(w io.Writer, g *Game) error {
+	r := g.Record
+
+	start, err := r.StartPosition()
+
+	if err != nil {
+		return err
+	}
+
+	ty := strconv.Itoa(r.Size)
+	if r.Rules == mrsoft.ANTI {
+		ty += "a"
+	}
+
+	result := fmt.Sprintf("%+.2f", float64(r.Black-r.White))
+
+	if g.Score != nil {
+		result = fmt.Sprintf("%+.2f", float64(*g.Score))
+
+		// the game was not played out
+		if pos, err := r.Position(); err == nil && !pos.IsFinish() {
+			result += ":r"
+		}
+	}
+
+	if r.TimeLoss != mrsoft.NONE {
+		result = fmt.Sprintf("%+.2f:t", float64(r.Size*r.Size*int(-r.TimeLoss)))
+	}
+
+	s := fmt.Sprintf("(;GM[Othello]PC[%s]DT[%s]PB[%s]PW[%s]TY[%s]RE[%s]BO[%s]", g.Event, g.Started.Format(ggfDate+".MST"), g.Black, g.White, ty, result, ggfBoard(&start))
+
+	positions, err := r.Positions()
+
+	if err != nil {
+		return err
+	}
+
+	color := map[mrsoft.Player]string{mrsoft.BLACK: "B", mrsoft.WHITE: "W"}
+
+	for i, m := range r.Moves {
+		turn := positions[i].Turn()
+
+		// passes omitted in the Record are written explicitly
+		if m != (mrsoft.Point{}) && len(positions[i].Moves()) == 0 {
+			s += color[turn] + "[PA]"
+			turn = -turn
+		}
+
+		s += fmt.Sprintf("%s[%s]", color[turn], strings.ToUpper(m.Notation()))
+	}
+
+	_, err = fmt.Fprintln(w, s+";)")
+
+	return err
+}
+
+// ggfBoard returns the board in BO property such as "8 -------- ... *"
+func ggfBoard(pos *mrsoft.Position) string {
+	d := strings.NewReplacer("X", "*").Replace(pos.Diagram())
+	n := pos.Size()
+
+	rows := []string{strconv.Itoa(n)}
+
+	for i := 0; i < n; i++ {
+		rows = append(rows, d[i*n:(i+1)*n])
+	}
+
+	return strings.Join(append(rows, d[n*n:]), " ")
+}
@@ -0,0 +1,121 @@
+package mrdb
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+const sampleGGF = `
+(;GM[Othello]PC[NOJ]DT[2003.12.15_01:31:08.MST]PB[alice]PW[bob]RE[+2.00]TI[5:00//02:00]
+TY[8]BO[8 -------- -------- -------- ---O*--- ---*O--- -------- -------- -------- *]
+B[f5//0.01]W[d6/1.23]B[C3];)
+(;GM[Othello]PB[carol]PW[dave]TY[4]BO[4 ---- -O*- -*O- ---- *]B[a2]W[a3]B[c4]W[a1]B[PA]W[d2];)
+`
+
+func TestReadGGF(t *testing.T) {
+	games, err := ReadGGF(strings.NewReader(sampleGGF))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(games) != 2 {
+		t.Fatalf("%d games are read, want 2", len(games))
+	}
+
+	g := games[0]
+
+	if g.Black != "alice" || g.White != "bob" || g.Event != "NOJ" || g.Started.Year() != 2003 {
+		t.Fatalf("game is %+v", g)
+	}
+
+	if s := mrsoft.FormatMoves(g.Record.Moves); s != "f5d6c3" || g.Record.Start != "" {
+		t.Fatalf("moves are %s from %q", s, g.Record.Start)
+	}
+
+	if s := mrsoft.FormatMoves(games[1].Record.Moves); games[1].Record.Size != 4 || s != "a2a3c4a1pad2" {
+		t.Fatalf("moves are %s on %dx%d", s, games[1].Record.Size, games[1].Record.Size)
+	}
+
+	for _, s := range []string{
+		"(;GM[Chess]B[e4];)",
+		"(;GM[Othello]TY[8]B[a1];)",
+		"(;GM[Othello]TY[8]B[f5]",
+	} {
+		if _, err = ReadGGF(strings.NewReader(s)); err == nil {
+			t.Fatalf("ReadGGF accepts %s", s)
+		}
+	}
+}
+
+func TestGGFResult(t *testing.T) {
+	// black resigns or loses on time with more discs on the board
+	for _, re := range []string{"-12.00:r", "-64.00:t"} {
+		s := "(;GM[Othello]PB[alice]PW[bob]RE[" + re + "]TY[8]B[f5]W[d6]B[c3];)"
+		games, err := ReadGGF(strings.NewReader(s))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if r := games[0].Result(); r != "white" {
+			t.Fatalf("result of %s is %s, want white", re, r)
+		}
+
+		buf := &bytes.Buffer{}
+
+		if err = WriteGGF(buf, games[0]); err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.Contains(buf.String(), "RE["+re+"]") {
+			t.Fatalf("result %s is written as %s", re, buf.String())
+		}
+	}
+}
+
+func TestWriteGGF(t *testing.T) {
+	games, err := ReadGGF(strings.NewReader(sampleGGF))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// passes omitted in the Record are written as well
+	r, err := mrsoft.ParseTranscript("standard 4 a2a3c4a1d2")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	games = append(games, &Game{Black: "erin", White: "frank", Record: r})
+
+	buf := &bytes.Buffer{}
+
+	for _, g := range games {
+		if err = WriteGGF(buf, g); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	written, err := ReadGGF(buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, g := range written {
+		want := mrsoft.FormatMoves(games[i].Record.Moves)
+
+		if i == 2 {
+			want = "a2a3c4a1pad2"
+		}
+
+		if s := mrsoft.FormatMoves(g.Record.Moves); s != want || g.Black != games[i].Black || g.Record.Black != games[i].Record.Black {
+			t.Fatalf("game %d is written as %s by %s, want %s by %s", i+1, s, g.Black, want, games[i].Black)
+		}
+	}
+}
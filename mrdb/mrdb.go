@@ -0,0 +1,232 @@
+package mrdb
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// Game is a finished game stored in the DB
+type Game struct {
+	ID int `json:"id"`
+	// Black and White are the names of the players
+	Black string `json:"black"`
+	White string `json:"white"`
+	// Event is the tournament or the place of the game
+	Event string `json:"event,omitempty"`
+	// Started and Finished are when the game was played
+	Started  time.Time      `json:"started"`
+	Finished time.Time      `json:"finished"`
+	Record   *mrsoft.Record `json:"record"`
+	// Score is the result for black given by the source, positive when black wins, such as of a resigned game.
+	// nil means the result is counted from the final discs.
+	Score *int `json:"score,omitempty"`
+	// Hashes are the symmetry-normalized hashes of all positions of the game
+	Hashes []uint64 `json:"hashes"`
+	// Rated means Black and White are IDs of profiles and the game changes their ratings
+	Rated bool `json:"rated,omitempty"`
+	// Rating is set by Add for rated games
+	Rating *Rating `json:"rating,omitempty"`
+}
+
+// Winner returns the winner by the stored Score if any, or by the Record, NONE on draw.
+// The Player who lost on time always loses.
+func (g *Game) Winner() mrsoft.Player {
+	if g.Score == nil || g.Record.TimeLoss != mrsoft.NONE {
+		return g.Record.Winner()
+	}
+
+	switch {
+	case *g.Score > 0:
+		return mrsoft.BLACK
+	case *g.Score < 0:
+		return mrsoft.WHITE
+	default:
+		return mrsoft.NONE
+	}
+}
+
+// Result returns "black", "white" or "draw"
+func (g *Game) Result() string {
+	switch g.Winner() {
+	case mrsoft.BLACK:
+		return "black"
+	case mrsoft.WHITE:
+		return "white"
+	default:
+		return "draw"
+	}
+}
+
+// DB is a game database stored in a file of JSON lines.
+// Games are appended to the file and never rewritten.
+type DB struct {
+	path  string
+	games []*Game
+	// positions indexes games by the hashes of their positions
+	positions map[uint64][]*Game
+}
+
+// Open loads the DB at path. The file is created by the first Add.
+func Open(path string) (*DB, error) {
+	db := &DB{path: path, positions: map[uint64][]*Game{}}
+
+	f, err := os.Open(path)
+
+	if os.IsNotExist(err) {
+		return db, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	s.Buffer(nil, 16*1024*1024)
+
+	for n := 1; s.Scan(); n++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+
+		g := &Game{}
+
+		if err = json.Unmarshal(s.Bytes(), g); err != nil {
+			return nil, fmt.Errorf("%s line %d: %s", path, n, err)
+		}
+
+		db.index(g)
+	}
+
+	return db, s.Err()
+}
+
+// Add stores the game with a new ID and the hashes of its positions
+func (db *DB) Add(g *Game) error {
+	positions, err := g.Record.Positions()
+
+	if err != nil {
+		return fmt.Errorf("Invalid record: %s", err)
+	}
+
+	if g.Rated {
+		if strings.EqualFold(g.Black, g.White) {
+			return fmt.Errorf("%s can't play a rated game against themselves", g.Black)
+		}
+
+		db.rate(g)
+	}
+
+	g.ID = 1
+	if len(db.games) != 0 {
+		g.ID = db.games[len(db.games)-1].ID + 1
+	}
+
+	g.Hashes = make([]uint64, len(positions))
+	for i := range positions {
+		g.Hashes[i] = positions[i].Hash()
+	}
+
+	line, err := json.Marshal(g)
+
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(db.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	db.index(g)
+
+	return nil
+}
+
+// index appends g to the games and the position index
+func (db *DB) index(g *Game) {
+	db.games = append(db.games, g)
+
+	seen := map[uint64]bool{}
+
+	for _, h := range g.Hashes {
+		if !seen[h] {
+			seen[h] = true
+			db.positions[h] = append(db.positions[h], g)
+		}
+	}
+}
+
+// Get returns the game of the ID
+func (db *DB) Get(id int) (*Game, bool) {
+	for _, g := range db.games {
+		if g.ID == id {
+			return g, true
+		}
+	}
+
+	return nil, false
+}
+
+// Games returns all games in the order of addition
+func (db *DB) Games() []*Game {
+	return db.games
+}
+
+// isInitial reports whether pos is the normal beginning of the board
+func isInitial(pos *mrsoft.Position) bool {
+	initial, _ := mrsoft.NewPosition(pos.Size())
+
+	return pos.Diagram() == initial.Diagram()
+}
+
+// nullMiddleware is a board without hardware to replay games
+type nullMiddleware struct{}
+
+func (nullMiddleware) Init() error {
+	return nil
+}
+
+func (nullMiddleware) GetInput() (int, int, error) {
+	return 0, 0, errors.New("No input")
+}
+
+func (nullMiddleware) Flip(int, int, mrmiddle.Pole) error {
+	return nil
+}
+
+// validate replays the moves of the game through mrsoft.Game
+// and counts the stones at the last position
+func validate(g *Game) error {
+	r := g.Record
+
+	mg, err := mrsoft.NewGame(nullMiddleware{}, mrsoft.Size(r.Size), mrsoft.Resume(r))
+
+	if err != nil {
+		return fmt.Errorf("Invalid moves: %s", err)
+	}
+
+	pos := mg.Position()
+	r.Black, r.White, _ = pos.Count()
+
+	return nil
+}
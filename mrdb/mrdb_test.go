@@ -0,0 +1,97 @@
+package mrdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+func newGame(t *testing.T, transcript, black, white string, started time.Time) *Game {
+	r, err := mrsoft.ParseTranscript(transcript)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Game{Black: black, White: white, Started: started, Finished: started.Add(time.Hour), Record: r}
+}
+
+func TestDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mrdb")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "games.jsonl")
+
+	db, err := Open(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	games := []*Game{
+		newGame(t, "standard 8 f5d6c3", "alice", "bob", day),
+		newGame(t, "standard 8 f5f6e6", "bob", "carol", day.AddDate(0, 0, 1)),
+		newGame(t, "standard 8 c4e3", "carol", "alice", day.AddDate(0, 0, 2)),
+	}
+
+	games[0].Record.Opening = "Tiger"
+
+	for _, g := range games {
+		if err = db.Add(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// reload from the file
+	if db, err = Open(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(db.Games()) != 3 || db.Games()[2].ID != 3 {
+		t.Fatalf("DB has %d games", len(db.Games()))
+	}
+
+	if g, ok := db.Get(2); !ok || g.Black != "bob" {
+		t.Fatalf("Get(2) returns %v", g)
+	}
+
+	// f5d6 is the same as c4e3 by the symmetry
+	pos := mrsoft.InitialPosition()
+	pos.Replay([]mrsoft.Point{{6, 5}, {4, 6}})
+
+	for _, c := range []struct {
+		q   Query
+		ids []int
+	}{
+		{Query{}, []int{1, 2, 3}},
+		{Query{Player: "Alice"}, []int{1, 3}},
+		{Query{Result: "draw"}, []int{3}},
+		{Query{From: day.AddDate(0, 0, 1), To: day.AddDate(0, 0, 2)}, []int{2}},
+		{Query{Opening: "tiger"}, []int{1}},
+		{Query{Position: &pos}, []int{1, 3}},
+		{Query{Player: "bob", Position: &pos}, []int{1}},
+	} {
+		found := db.Find(c.q)
+
+		if len(found) != len(c.ids) {
+			t.Fatalf("Find(%+v) returns %d games, want %v", c.q, len(found), c.ids)
+		}
+
+		for i, g := range found {
+			if g.ID != c.ids[i] {
+				t.Fatalf("Find(%+v) returns game %d, want %v", c.q, g.ID, c.ids)
+			}
+		}
+	}
+}
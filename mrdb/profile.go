@@ -0,0 +1,89 @@
+package mrdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Profile is a registered player of the ladder
+type Profile struct {
+	// ID is the identifier used in games and on the command line
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Profiles is the list of players stored in a JSON file
+type Profiles struct {
+	path     string
+	profiles []Profile
+}
+
+// OpenProfiles loads the Profiles at path. The file is created by the first Add.
+func OpenProfiles(path string) (*Profiles, error) {
+	ps := &Profiles{path: path}
+
+	data, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return ps, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, &ps.profiles); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return ps, nil
+}
+
+// Add registers the Profile and saves all profiles
+func (ps *Profiles) Add(p Profile) error {
+	if p.ID == "" || strings.ContainsAny(p.ID, " \t") {
+		return fmt.Errorf("Invalid player ID: %q", p.ID)
+	}
+
+	if _, ok := ps.Get(p.ID); ok {
+		return fmt.Errorf("Player %s already exists", p.ID)
+	}
+
+	if p.Name == "" {
+		return errors.New("Player name is empty")
+	}
+
+	data, err := json.MarshalIndent(append(ps.profiles, p), "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	if err = ioutil.WriteFile(ps.path, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+
+	ps.profiles = append(ps.profiles, p)
+
+	return nil
+}
+
+// Get returns the Profile of the ID, which is case-insensitive
+func (ps *Profiles) Get(id string) (Profile, bool) {
+	for _, p := range ps.profiles {
+		if strings.EqualFold(p.ID, id) {
+			return p, true
+		}
+	}
+
+	return Profile{}, false
+}
+
+// List returns all profiles in the order of registration
+func (ps *Profiles) List() []Profile {
+	return ps.profiles
+}
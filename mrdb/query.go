@@ -0,0 +1,58 @@
+package mrdb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// Query is the condition of Find. Zero fields match any game.
+type Query struct {
+	// Player is the name of either player
+	Player string
+	// Result is "black", "white" or "draw"
+	Result string
+	// From and To are the range of the time the game started
+	From, To time.Time
+	// Opening is the name of the opening
+	Opening string
+	// Position is a position reached in the game under any symmetry
+	Position *mrsoft.Position
+}
+
+// match reports whether the game satisfies q
+func (q *Query) match(g *Game) bool {
+	switch {
+	case q.Player != "" && !strings.EqualFold(g.Black, q.Player) && !strings.EqualFold(g.White, q.Player):
+		return false
+	case q.Result != "" && !strings.EqualFold(g.Result(), q.Result):
+		return false
+	case !q.From.IsZero() && g.Started.Before(q.From):
+		return false
+	case !q.To.IsZero() && !g.Started.Before(q.To):
+		return false
+	case q.Opening != "" && !strings.EqualFold(g.Record.Opening, q.Opening):
+		return false
+	default:
+		return true
+	}
+}
+
+// Find returns the games which match q
+func (db *DB) Find(q Query) (games []*Game) {
+	candidates := db.games
+
+	// games reaching the position are looked up by the index
+	if q.Position != nil {
+		candidates = db.positions[q.Position.Hash()]
+	}
+
+	for _, g := range candidates {
+		if q.match(g) {
+			games = append(games, g)
+		}
+	}
+
+	return
+}
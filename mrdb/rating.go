@@ -0,0 +1,144 @@
+package mrdb
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+const (
+	// InitialRating is the Elo rating of a player without rated games
+	InitialRating = 1500.0
+	// KFactor is the maximum change of the rating by a game
+	KFactor = 32.0
+)
+
+// Rating is the Elo ratings after a rated game
+type Rating struct {
+	Black float64 `json:"black"`
+	White float64 `json:"white"`
+	// Change is the points gained by black and lost by white
+	Change float64 `json:"change"`
+}
+
+// Expected returns the expected score per game of the player rated a against b
+func Expected(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+// Rating returns the current rating of the player
+func (db *DB) Rating(id string) float64 {
+	for i := len(db.games) - 1; i >= 0; i-- {
+		g := db.games[i]
+
+		switch {
+		case g.Rating == nil:
+		case strings.EqualFold(g.Black, id):
+			return g.Rating.Black
+		case strings.EqualFold(g.White, id):
+			return g.Rating.White
+		}
+	}
+
+	return InitialRating
+}
+
+// rate sets the Rating of g by the current ratings of the players
+func (db *DB) rate(g *Game) {
+	black, white := db.Rating(g.Black), db.Rating(g.White)
+
+	score := 0.5
+	switch g.Winner() {
+	case mrsoft.BLACK:
+		score = 1
+	case mrsoft.WHITE:
+		score = 0
+	}
+
+	change := KFactor * (score - Expected(black, white))
+
+	g.Rating = &Rating{Black: black + change, White: white - change, Change: change}
+}
+
+// Standing is the rated results of a player
+type Standing struct {
+	ID                         string
+	Rating                     float64
+	Games, Wins, Draws, Losses int
+}
+
+// add counts the result of g for the player of color
+func (s *Standing) add(g *Game, color mrsoft.Player) {
+	s.Games++
+
+	switch g.Winner() {
+	case color:
+		s.Wins++
+	case mrsoft.NONE:
+		s.Draws++
+	default:
+		s.Losses++
+	}
+}
+
+// Leaderboard returns the Standings of all players of rated games
+// in descending order of the rating
+func (db *DB) Leaderboard() []Standing {
+	standings := map[string]*Standing{}
+
+	for _, g := range db.games {
+		if g.Rating == nil {
+			continue
+		}
+
+		for _, color := range []mrsoft.Player{mrsoft.BLACK, mrsoft.WHITE} {
+			id, rating := g.Black, g.Rating.Black
+			if color == mrsoft.WHITE {
+				id, rating = g.White, g.Rating.White
+			}
+
+			key := strings.ToLower(id)
+
+			if standings[key] == nil {
+				standings[key] = &Standing{ID: id}
+			}
+
+			standings[key].Rating = rating
+			standings[key].add(g, color)
+		}
+	}
+
+	board := []Standing{}
+	for _, s := range standings {
+		board = append(board, *s)
+	}
+
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Rating != board[j].Rating {
+			return board[i].Rating > board[j].Rating
+		}
+
+		return board[i].ID < board[j].ID
+	})
+
+	return board
+}
+
+// HeadToHead returns the rated results of the player a against b
+func (db *DB) HeadToHead(a, b string) Standing {
+	s := Standing{ID: a, Rating: db.Rating(a)}
+
+	for _, g := range db.games {
+		switch {
+		case g.Rating == nil:
+		case strings.EqualFold(g.Black, a) && strings.EqualFold(g.White, b):
+			s.add(g, mrsoft.BLACK)
+		case strings.EqualFold(g.White, a) && strings.EqualFold(g.Black, b):
+			s.add(g, mrsoft.WHITE)
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,93 @@
+package mrdb
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRating(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mrdb")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	ps, err := OpenProfiles(filepath.Join(dir, "profiles.json"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []Profile{{"alice", "Alice"}, {"bob", "Bob"}} {
+		if err = ps.Add(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = ps.Add(Profile{"Alice", "Another Alice"}); err == nil {
+		t.Fatal("Add accepts the same ID")
+	}
+
+	if ps, err = OpenProfiles(filepath.Join(dir, "profiles.json")); err != nil || len(ps.List()) != 2 {
+		t.Fatalf("OpenProfiles returns %v, %v", ps, err)
+	}
+
+	db, err := Open(filepath.Join(dir, "games.jsonl"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	games := []*Game{
+		newGame(t, "standard 8 f5d6c3d3c4", "alice", "bob", day),
+		newGame(t, "standard 8 f5d6c3", "alice", "bob", day),
+		newGame(t, "standard 8 f5", "bob", "alice", day),
+	}
+
+	for _, g := range games {
+		g.Rated = true
+
+		if err = db.Add(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = db.Add(&Game{Black: "bob", White: "BOB", Rated: true, Record: games[1].Record}); err == nil {
+		t.Fatal("Add accepts a rated game against oneself")
+	}
+
+	// unrated games don't change the ratings
+	if err = db.Add(newGame(t, "standard 8 f5", "alice", "bob", day)); err != nil {
+		t.Fatal(err)
+	}
+
+	a, b := db.Rating("alice"), db.Rating("Bob")
+
+	if math.Abs(a+b-2*InitialRating) > 1e-9 || a == InitialRating {
+		t.Fatalf("Ratings are %f and %f", a, b)
+	}
+
+	board := db.Leaderboard()
+
+	if len(board) != 2 || board[0].Rating < board[1].Rating || board[0].Games != 3 {
+		t.Fatalf("Leaderboard is %+v", board)
+	}
+
+	h := db.HeadToHead("alice", "bob")
+
+	if h.Games != 3 || h.Wins+h.Draws+h.Losses != 3 {
+		t.Fatalf("HeadToHead is %+v", h)
+	}
+
+	if e := Expected(1600, 1400); math.Abs(e-0.7597) > 1e-4 {
+		t.Fatalf("Expected(1600, 1400) is %f", e)
+	}
+}
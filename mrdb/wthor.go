@@ -0,0 +1,169 @@
+package mrdb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// wthorHeader is the header of all WTHOR files
+type wthorHeader struct {
+	Century, Year, Month, Day byte
+	// N1 is the number of games and N2 is the number of players or tournaments
+	N1 uint32
+	N2 uint16
+	// GameYear is the year when the games were played
+	GameYear uint16
+	// BoardSize is 0 or 8 for 8 x 8 board
+	BoardSize byte
+	Solitaire byte
+	Depth     byte
+	Reserved  byte
+}
+
+// wthorGame is a game of .wtb file
+type wthorGame struct {
+	Tournament, Black, White uint16
+	// Score is the number of black stones and Theoretical is the one by perfect play
+	Score, Theoretical byte
+	// Moves are 10 * y + x, followed by 0 after the last move
+	Moves [60]byte
+}
+
+// ReadWTHOR reads games of .wtb file with the names of players of .JOU file
+// and tournaments of .TRN file, which may be nil.
+// Games are validated by replaying through mrsoft.Game.
+func ReadWTHOR(wtb, players, tournaments io.Reader) ([]*Game, error) {
+	h := wthorHeader{}
+
+	if err := binary.Read(wtb, binary.LittleEndian, &h); err != nil {
+		return nil, fmt.Errorf("Invalid WTHOR header: %s", err)
+	}
+
+	if h.BoardSize != 0 && h.BoardSize != 8 {
+		return nil, fmt.Errorf("Only 8x8 WTHOR is supported, not %dx%d", h.BoardSize, h.BoardSize)
+	}
+
+	playerNames, err := readWTHORNames(players, 20)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid WTHOR players: %s", err)
+	}
+
+	tournamentNames, err := readWTHORNames(tournaments, 26)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid WTHOR tournaments: %s", err)
+	}
+
+	games := make([]*Game, h.N1)
+
+	for i := range games {
+		wg := wthorGame{}
+
+		if err := binary.Read(wtb, binary.LittleEndian, &wg); err != nil {
+			return nil, fmt.Errorf("WTHOR game %d: %s", i+1, err)
+		}
+
+		// the blank cells of a game finished early are counted for the winner
+		score := 2*int(wg.Score) - 64
+
+		g := &Game{
+			Score:   &score,
+			Black:   wthorName(playerNames, wg.Black),
+			White:   wthorName(playerNames, wg.White),
+			Event:   wthorName(tournamentNames, wg.Tournament),
+			Started: time.Date(int(h.GameYear), 1, 1, 0, 0, 0, 0, time.UTC),
+			Record:  &mrsoft.Record{Size: 8, Rules: mrsoft.STANDARD},
+		}
+
+		for _, m := range wg.Moves {
+			if m == 0 {
+				break
+			}
+
+			g.Record.Moves = append(g.Record.Moves, mrsoft.Point{int(m % 10), int(m / 10)})
+		}
+
+		if err := validate(g); err != nil {
+			return nil, fmt.Errorf("WTHOR game %d: %s", i+1, err)
+		}
+
+		games[i] = g
+	}
+
+	return games, nil
+}
+
+// LoadWTHOR reads the .wtb file at path with .JOU and .TRN files which may be empty
+func LoadWTHOR(path, playersPath, tournamentsPath string) ([]*Game, error) {
+	files := []io.Reader{}
+
+	for _, p := range []string{path, playersPath, tournamentsPath} {
+		if p == "" {
+			files = append(files, nil)
+			continue
+		}
+
+		f, err := os.Open(p)
+
+		if err != nil {
+			return nil, err
+		}
+
+		defer f.Close()
+
+		files = append(files, f)
+	}
+
+	return ReadWTHOR(files[0], files[1], files[2])
+}
+
+// readWTHORNames reads names of the records of size bytes, nil if r is nil
+func readWTHORNames(r io.Reader, size int) (names []string, err error) {
+	if r == nil {
+		return
+	}
+
+	h := wthorHeader{}
+
+	if err = binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return
+	}
+
+	buf := make([]byte, size)
+
+	for i := 0; i < int(h.N2); i++ {
+		if _, err = io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+
+		// names are null-terminated Latin-1
+		name := []rune{}
+
+		for _, c := range buf {
+			if c == 0 {
+				break
+			}
+
+			name = append(name, rune(c))
+		}
+
+		names = append(names, string(name))
+	}
+
+	return
+}
+
+// wthorName returns the i-th name, or the number if it is unknown
+func wthorName(names []string, i uint16) string {
+	if int(i) < len(names) {
+		return names[i]
+	}
+
+	return fmt.Sprintf("#%d", i)
+}
@@ -0,0 +1,74 @@
+package mrdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// wthorNames returns a WTHOR file of names in records of size bytes
+func wthorNames(names []string, size int) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	binary.Write(buf, binary.LittleEndian, wthorHeader{N2: uint16(len(names))})
+
+	for _, name := range names {
+		rec := make([]byte, size)
+		copy(rec, name)
+		buf.Write(rec)
+	}
+
+	return buf
+}
+
+func TestReadWTHOR(t *testing.T) {
+	wtb := &bytes.Buffer{}
+	binary.Write(wtb, binary.LittleEndian, wthorHeader{N1: 1, GameYear: 2001, BoardSize: 8})
+
+	// f5 d6 c3
+	g := wthorGame{Tournament: 1, Black: 0, White: 2, Score: 32}
+	copy(g.Moves[:], []byte{56, 64, 33})
+	binary.Write(wtb, binary.LittleEndian, g)
+
+	games, err := ReadWTHOR(wtb, wthorNames([]string{"alice", "bob"}, 20), wthorNames([]string{"Open", "Cup"}, 26))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(games) != 1 {
+		t.Fatalf("%d games are read, want 1", len(games))
+	}
+
+	if r := games[0]; r.Black != "alice" || r.White != "#2" || r.Event != "Cup" || r.Started.Year() != 2001 {
+		t.Fatalf("game is %+v", r)
+	}
+
+	if s := mrsoft.FormatMoves(games[0].Record.Moves); s != "f5d6c3" {
+		t.Fatalf("moves are %s, want f5d6c3", s)
+	}
+
+	// white won by 24 discs after black resigned
+	wtb.Reset()
+	binary.Write(wtb, binary.LittleEndian, wthorHeader{N1: 1, BoardSize: 8})
+	g.Score = 20
+	binary.Write(wtb, binary.LittleEndian, g)
+
+	if games, err = ReadWTHOR(wtb, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := games[0].Result(); r != "white" || *games[0].Score != -24 {
+		t.Fatalf("result is %s by %d, want white by 24", r, *games[0].Score)
+	}
+
+	// a1 is illegal
+	wtb.Reset()
+	binary.Write(wtb, binary.LittleEndian, wthorHeader{N1: 1})
+	binary.Write(wtb, binary.LittleEndian, wthorGame{Moves: [60]byte{11}})
+
+	if _, err = ReadWTHOR(wtb, nil, nil); err == nil {
+		t.Fatal("ReadWTHOR accepts illegal moves")
+	}
+}
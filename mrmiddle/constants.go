@@ -80,6 +80,9 @@ const (
 
 	// FLIPTIME is the time of output for flip
 	FLIPTIME = 500 * time.Millisecond
+
+	// SIZE is the number of cells on a side of the board
+	SIZE = 8
 )
 
 // Pole represents magnetic poll direction
@@ -92,3 +95,36 @@ const (
 	// S pole
 	S Pole = -1
 )
+
+func (pd Pole) String() string {
+	switch pd {
+	case N:
+		return "N"
+	case S:
+		return "S"
+	default:
+		return "NONE"
+	}
+}
+
+// Command represents a request given from other than the board.
+// GetInput returns (x, y) = (Command, Command) for it.
+type Command int
+
+const (
+	// UNDO requests to undo the last move
+	UNDO Command = -1 - iota
+	// HINT requests the recommended move
+	HINT
+	// REMOVE reports that a stone is lifted from the board.
+	// MrMiddle.Removed tells where it was.
+	REMOVE
+	// REDO requests to put the undone move again
+	REDO
+	// PAUSE requests to stop the game until RESUME
+	PAUSE
+	// RESUME requests to continue the paused game
+	RESUME
+	// NEWGAME requests to abandon the game and start a new one
+	NEWGAME
+)
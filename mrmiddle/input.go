@@ -1,18 +1,24 @@
 package mrmiddle
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// ErrTimeout is returned by GetInputTimeout when no stone is put in time
+var ErrTimeout = errors.New("Timeout")
 
 // read given y line
 func (mm *MrMiddle) readLine(y int) (r row, err error) {
 	addr, gpio := y2AddrAndGpio(y)
 
-	mm.e.I2cStart(addr)
+	mm.i2cStart(addr)
 
-	if err = mm.e.I2cWrite(addr, []byte{byte(gpio)}); checkError(err) {
+	if err = mm.i2cWrite(addr, []byte{byte(gpio)}); checkError(err) {
 		return row{}, wrapError(err)
 	}
 
-	data, err := mm.e.I2cRead(addr, 1)
+	data, err := mm.i2cRead(addr, 1)
 
 	if checkError(err) {
 		return row{}, wrapError(err)
@@ -29,13 +35,13 @@ func (mm *MrMiddle) readLine(y int) (r row, err error) {
 
 // read both A and B bits of given address of the Expander
 func (mm MrMiddle) readAB(addr int) (byteSet [2]row, err error) {
-	mm.e.I2cStart(addr)
+	mm.i2cStart(addr)
 
-	if err = mm.e.I2cWrite(addr, []byte{GPIOA}); checkError(err) {
+	if err = mm.i2cWrite(addr, []byte{GPIOA}); checkError(err) {
 		return [2]row{}, wrapError(err)
 	}
 
-	data, err := mm.e.I2cRead(addr, 2)
+	data, err := mm.i2cRead(addr, 2)
 
 	if checkError(err) {
 		return [2]row{}, wrapError(err)
@@ -65,16 +71,33 @@ func (mm *MrMiddle) readWholeBoard() (byteSet [8]row, err error) {
 
 // GetInput waits until board state changes and return x, y
 func (mm *MrMiddle) GetInput() (int, int, error) {
+	return mm.GetInputTimeout(0)
+}
+
+// GetInputTimeout is GetInput which returns ErrTimeout after d. d <= 0 means no limit.
+func (mm *MrMiddle) GetInputTimeout(d time.Duration) (int, int, error) {
+	begin := time.Now()
+	deadline := begin.Add(d)
+
 	old, err := mm.readWholeBoard()
 
 	if checkError(err) {
+		mm.inputDone(begin, "error", 0, 0)
 		return 0, 0, wrapError(err)
 	}
 
 	for {
+		select {
+		case c := <-mm.commands:
+			mm.inputDone(begin, "command", 0, 0)
+			return int(c), int(c), nil
+		default:
+		}
+
 		crr, err := mm.readWholeBoard()
 
 		if checkError(err) {
+			mm.inputDone(begin, "error", 0, 0)
 			return 0, 0, wrapError(err)
 		}
 
@@ -83,12 +106,55 @@ func (mm *MrMiddle) GetInput() (int, int, error) {
 				for j, v := range r {
 					// if current status is True and old status is False then reutrn (x, y)
 					if v && !old[i][j] {
+						mm.inputDone(begin, "put", j+1, i+1)
 						return j + 1, i + 1, nil
 					}
+
+					// if a stone is lifted then return REMOVE
+					if !v && old[i][j] {
+						mm.removed = [2]int{j + 1, i + 1}
+						mm.inputDone(begin, "remove", j+1, i+1)
+						return int(REMOVE), int(REMOVE), nil
+					}
 				}
 			}
 		}
 
+		if d > 0 && time.Now().After(deadline) {
+			mm.inputDone(begin, "timeout", 0, 0)
+			return 0, 0, ErrTimeout
+		}
+
 		time.Sleep(POLLTIME)
 	}
 }
+
+// Removed returns (x, y) of the stone lifted when GetInput returned REMOVE
+func (mm *MrMiddle) Removed() (int, int) {
+	return mm.removed[0], mm.removed[1]
+}
+
+// Send makes GetInput return the Command.
+// The Command is dropped when too many commands are waiting not to block the sender.
+func (mm *MrMiddle) Send(c Command) {
+	select {
+	case mm.commands <- c:
+	default:
+		mm.log.WithField("command", int(c)).Warn("Command is dropped since too many commands are waiting")
+	}
+}
+
+// Sense returns whether a stone is on each cell, indexed by [y-1][x-1]
+func (mm *MrMiddle) Sense() (cells [SIZE][SIZE]bool, err error) {
+	rows, err := mm.readWholeBoard()
+
+	if checkError(err) {
+		return cells, wrapError(err)
+	}
+
+	for i, r := range rows {
+		cells[i] = [SIZE]bool(r)
+	}
+
+	return
+}
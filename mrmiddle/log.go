@@ -0,0 +1,78 @@
+package mrmiddle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetLogger sets the logger of the events of the board.
+// I2C operations are logged at the trace level, coils and inputs at the debug level.
+func (mm *MrMiddle) SetLogger(l logrus.FieldLogger) {
+	mm.log = l.WithField("component", "mrmiddle")
+}
+
+// i2cDone counts and logs an I2C operation on addr
+func (mm *MrMiddle) i2cDone(addr int, op string, data []byte, err error) {
+	countI2c(addr, op, err)
+
+	l := mm.log.WithFields(logrus.Fields{
+		"address": fmt.Sprintf("0x%02x", addr),
+		"op":      op,
+	})
+
+	if data != nil {
+		l = l.WithField("data", fmt.Sprintf("%x", data))
+	}
+
+	if i, ok := expanderIndex(addr); ok {
+		// a write to GPIOA or GPIOB of an output expander drives one row
+		if op == opWrite && len(data) == 2 && (data[0] == GPIOA || data[0] == GPIOB) {
+			l = l.WithField("y", 2*i+1+int(data[0]-GPIOA))
+		} else {
+			l = l.WithField("rows", fmt.Sprintf("%d-%d", 2*i+1, 2*i+2))
+		}
+	}
+
+	if err != nil {
+		l.WithError(err).Error("I2C operation failed")
+		return
+	}
+
+	l.Trace("I2C operation")
+}
+
+// expanderIndex returns the index of the expander at addr, which handles the rows 2i+1 and 2i+2
+func expanderIndex(addr int) (int, bool) {
+	for i := range EXIA {
+		if EXIA[i] == addr || EXOA[i] == addr {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+// inputDone counts and logs the result of GetInput waiting since begin
+func (mm *MrMiddle) inputDone(begin time.Time, result string, x, y int) {
+	wait := time.Since(begin)
+
+	observeInput(wait, result)
+
+	l := mm.log.WithFields(logrus.Fields{
+		"result": result,
+		"wait":   wait.Seconds(),
+	})
+
+	if x > 0 {
+		l = l.WithFields(logrus.Fields{"x": x, "y": y})
+	}
+
+	l.Debug("Input")
+}
+
+// cellLog returns the logger of the cell (x, y)
+func (mm *MrMiddle) cellLog(x, y int) logrus.FieldLogger {
+	return mm.log.WithFields(logrus.Fields{"x": x, "y": y})
+}
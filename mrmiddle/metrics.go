@@ -0,0 +1,118 @@
+package mrmiddle
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metric labels of I2C operations
+const (
+	opStart = "start"
+	opRead  = "read"
+	opWrite = "write"
+)
+
+var (
+	i2cOps = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "magicreversi",
+		Subsystem: "i2c",
+		Name:      "operations_total",
+		Help:      "Number of I2C operations by expander address and operation.",
+	}, []string{"address", "op"})
+
+	i2cErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "magicreversi",
+		Subsystem: "i2c",
+		Name:      "errors_total",
+		Help:      "Number of failed I2C operations by expander address and operation.",
+	}, []string{"address", "op"})
+
+	flipPulses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "magicreversi",
+		Subsystem: "flip",
+		Name:      "pulses_total",
+		Help:      "Number of coil pulses to flip stones by cell.",
+	}, []string{"x", "y"})
+
+	flipFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "magicreversi",
+		Subsystem: "flip",
+		Name:      "failures_total",
+		Help:      "Number of failed flips by cell.",
+	}, []string{"x", "y"})
+
+	coilOnTime = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "magicreversi",
+		Subsystem: "coil",
+		Name:      "on_seconds",
+		Help:      "Time the coils are driven per pulse.",
+		Buckets:   prometheus.LinearBuckets(0.05, 0.05, 10),
+	})
+
+	inputWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "magicreversi",
+		Subsystem: "input",
+		Name:      "wait_seconds",
+		Help:      "Time GetInput waits by result (put, remove, command, timeout or error).",
+		Buckets:   prometheus.ExponentialBuckets(0.5, 2, 10),
+	}, []string{"result"})
+)
+
+// RegisterMetrics registers the metrics of the board to r
+func RegisterMetrics(r prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{i2cOps, i2cErrors, flipPulses, flipFailures, coilOnTime, inputWait} {
+		if err := r.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// countI2c counts an I2C operation on addr and its error
+func countI2c(addr int, op string, err error) {
+	a := fmt.Sprintf("0x%02x", addr)
+
+	i2cOps.WithLabelValues(a, op).Inc()
+
+	if err != nil {
+		i2cErrors.WithLabelValues(a, op).Inc()
+	}
+}
+
+// i2cStart starts I2C communication with addr
+func (mm *MrMiddle) i2cStart(addr int) (err error) {
+	err = mm.e.I2cStart(addr)
+	mm.i2cDone(addr, opStart, nil, err)
+
+	return
+}
+
+// i2cWrite writes data to addr
+func (mm *MrMiddle) i2cWrite(addr int, data []byte) (err error) {
+	err = mm.e.I2cWrite(addr, data)
+	mm.i2cDone(addr, opWrite, data, err)
+
+	return
+}
+
+// i2cRead reads n bytes from addr
+func (mm *MrMiddle) i2cRead(addr int, n int) (data []byte, err error) {
+	data, err = mm.e.I2cRead(addr, n)
+	mm.i2cDone(addr, opRead, data, err)
+
+	return
+}
+
+// observeInput observes the time GetInput waited
+func observeInput(wait time.Duration, result string) {
+	inputWait.WithLabelValues(result).Observe(wait.Seconds())
+}
+
+// cellLabels returns the labels of (x, y)
+func cellLabels(x, y int) []string {
+	return []string{strconv.Itoa(x), strconv.Itoa(y)}
+}
@@ -2,10 +2,10 @@ package mrmiddle
 
 import (
 	"fmt"
-	"log"
 	"time"
 
 	multierror "github.com/hashicorp/go-multierror"
+	"github.com/sirupsen/logrus"
 
 	"gobot.io/x/gobot/platforms/intel-iot/edison"
 )
@@ -13,11 +13,17 @@ import (
 // MrMiddle is Magic Reversi's middle ware object
 type MrMiddle struct {
 	e *edison.Adaptor
+	// commands sent from other than the board
+	commands chan Command
+	// (x, y) of the stone lifted last
+	removed [2]int
+	log     logrus.FieldLogger
 }
 
 // NewMrMiddle returns MrMiddle instance
 func NewMrMiddle() (mm *MrMiddle, err error) {
-	mm = &MrMiddle{}
+	mm = &MrMiddle{commands: make(chan Command, 8)}
+	mm.SetLogger(logrus.StandardLogger())
 
 	mm.e = edison.NewAdaptor()
 
@@ -85,9 +91,14 @@ func y2AddrAndGpio(y int) (addr int, gpio int) {
 	return
 }
 
+// Size returns the number of cells on a side of the board
+func (mm *MrMiddle) Size() int {
+	return SIZE
+}
+
 // Init is initialization function of MrMiddle
 func (mm *MrMiddle) Init() (err error) {
-	log.Println("Initialize circuit...")
+	mm.log.Info("Initialize circuit")
 
 	if e := export(IN1); checkError(e) {
 		err = multierror.Append(err, wrapError(e))
@@ -102,37 +113,37 @@ func (mm *MrMiddle) Init() (err error) {
 	}
 
 	for _, addr := range EXIA {
-		mm.e.I2cStart(addr)
+		mm.i2cStart(addr)
 
 		//　Initialize IOCON
-		if e := mm.e.I2cWrite(addr, []byte{IOCON, 0x00}); checkError(e) {
+		if e := mm.i2cWrite(addr, []byte{IOCON, 0x00}); checkError(e) {
 			err = multierror.Append(err, wrapError(e))
 		}
 
 		// Initialize IODIR as read
-		if e := mm.e.I2cWrite(addr, []byte{IODIRA, 0xFF}); checkError(e) {
+		if e := mm.i2cWrite(addr, []byte{IODIRA, 0xFF}); checkError(e) {
 			err = multierror.Append(err, wrapError(e))
 		}
 
-		if e := mm.e.I2cWrite(addr, []byte{IODIRB, 0xFF}); checkError(e) {
+		if e := mm.i2cWrite(addr, []byte{IODIRB, 0xFF}); checkError(e) {
 			err = multierror.Append(err, wrapError(e))
 		}
 	}
 
 	for _, addr := range EXOA {
-		mm.e.I2cStart(addr)
+		mm.i2cStart(addr)
 
 		//　Initialize IOCON
-		if e := mm.e.I2cWrite(addr, []byte{IOCON, 0x00}); checkError(e) {
+		if e := mm.i2cWrite(addr, []byte{IOCON, 0x00}); checkError(e) {
 			err = multierror.Append(err, wrapError(e))
 		}
 
 		// Initialize IODIR as write
-		if e := mm.e.I2cWrite(addr, []byte{IODIRA, 0x00}); checkError(e) {
+		if e := mm.i2cWrite(addr, []byte{IODIRA, 0x00}); checkError(e) {
 			err = multierror.Append(err, wrapError(e))
 		}
 
-		if e := mm.e.I2cWrite(addr, []byte{IODIRB, 0x00}); checkError(e) {
+		if e := mm.i2cWrite(addr, []byte{IODIRB, 0x00}); checkError(e) {
 			err = multierror.Append(err, wrapError(e))
 		}
 	}
@@ -146,7 +157,7 @@ func (mm *MrMiddle) Init() (err error) {
 
 // Finalize execute finalizing process
 func (mm *MrMiddle) Finalize() (err error) {
-	fmt.Println("Finalize...")
+	mm.log.Info("Finalize")
 	if e := mm.releaseCoil(); checkError(e) {
 		err = multierror.Append(err, wrapError(e))
 	}
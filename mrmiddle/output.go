@@ -1,12 +1,16 @@
 package mrmiddle
 
-import "time"
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
 
 // write byte data to designated line
 func (mm *MrMiddle) writeByte(y int, v byte) (err error) {
 	addr, gpio := y2AddrAndGpio(y - 1)
 
-	if err = mm.e.I2cStart(addr); checkError(err) {
+	if err = mm.i2cStart(addr); checkError(err) {
 		return
 	}
 
@@ -16,7 +20,7 @@ func (mm *MrMiddle) writeByte(y int, v byte) (err error) {
 
 	data := []byte{byte(gpio), v}
 
-	return mm.e.I2cWrite(addr, data)
+	return mm.i2cWrite(addr, data)
 }
 
 func (mm *MrMiddle) writeAllLow() (err error) {
@@ -82,13 +86,21 @@ func (mm *MrMiddle) highWhile(x int, y int, ms time.Duration, pd Pole) (err erro
 		return
 	}
 
+	on := time.Now()
+
 	if err = mm.driveCoil(pd); checkError(err) {
 		return
 	}
 
 	time.Sleep(ms)
 
-	if err = mm.releaseCoil(); checkError(err) {
+	err = mm.releaseCoil()
+	onTime := time.Since(on)
+	coilOnTime.Observe(onTime.Seconds())
+
+	mm.cellLog(x, y).WithFields(logrus.Fields{"pole": pd, "on": onTime.Seconds()}).Debug("Coil pulse")
+
+	if checkError(err) {
 		return
 	}
 
@@ -97,9 +109,13 @@ func (mm *MrMiddle) highWhile(x int, y int, ms time.Duration, pd Pole) (err erro
 
 // Flip flips a stone at (x, y)
 func (mm *MrMiddle) Flip(x int, y int, pd Pole) (err error) {
+	flipPulses.WithLabelValues(cellLabels(x, y)...).Inc()
+
 	err = mm.highWhile(x, y, FLIPTIME, pd)
 
 	if checkError(err) {
+		flipFailures.WithLabelValues(cellLabels(x, y)...).Inc()
+		mm.cellLog(x, y).WithField("pole", pd).WithError(err).Error("Flip failed")
 		return wrapError(err)
 	}
 
@@ -0,0 +1,107 @@
+package mrmqtt
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrTimeout is returned when the broker doesn't answer in time
+var ErrTimeout = errors.New("MQTT broker timed out")
+
+// timeout is the time to wait for the broker
+const timeout = 5 * time.Second
+
+// Handler is called with the messages of a subscribed topic
+type Handler func(topic string, payload []byte)
+
+// Client is the connection to an MQTT broker used by the Bridge.
+// Publish may return before the message reaches the broker not to block the Game.
+type Client interface {
+	Publish(topic string, retained bool, payload []byte) error
+	Subscribe(topic string, h Handler) error
+	Disconnect()
+}
+
+// pahoClient is a Client by the Eclipse Paho library, which subscribes again after reconnecting
+type pahoClient struct {
+	c    mqtt.Client
+	log  logrus.FieldLogger
+	mu   sync.Mutex
+	subs map[string]Handler
+}
+
+// Dial connects to the broker such as "tcp://localhost:1883" as clientID.
+// Messages are sent at least once and the connection is restored when it is lost.
+// Messages failed to be published are logged to l.
+func Dial(broker, clientID string, l logrus.FieldLogger) (Client, error) {
+	p := &pahoClient{log: l.WithField("component", "mrmqtt"), subs: map[string]Handler{}}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(clientID).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(func(mqtt.Client) { p.resubscribe() })
+
+	p.c = mqtt.NewClient(opts)
+
+	if err := wait(p.c.Connect()); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// wait waits for the token of an operation
+func wait(t mqtt.Token) error {
+	if !t.WaitTimeout(timeout) {
+		return ErrTimeout
+	}
+
+	return t.Error()
+}
+
+// Publish returns without waiting for the broker, which is waited for by another goroutine
+func (p *pahoClient) Publish(topic string, retained bool, payload []byte) error {
+	t := p.c.Publish(topic, 1, retained, payload)
+
+	go func() {
+		if err := wait(t); err != nil {
+			p.log.WithField("topic", topic).WithError(err).Warn("Failed to publish")
+		}
+	}()
+
+	return nil
+}
+
+func (p *pahoClient) Subscribe(topic string, h Handler) error {
+	p.mu.Lock()
+	p.subs[topic] = h
+	p.mu.Unlock()
+
+	return wait(p.subscribe(topic, h))
+}
+
+// subscribe subscribes to the topic without waiting
+func (p *pahoClient) subscribe(topic string, h Handler) mqtt.Token {
+	return p.c.Subscribe(topic, 1, func(_ mqtt.Client, m mqtt.Message) {
+		h(m.Topic(), m.Payload())
+	})
+}
+
+// resubscribe subscribes to the topics again since the broker may have forgotten them
+func (p *pahoClient) resubscribe() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for topic, h := range p.subs {
+		p.subscribe(topic, h)
+	}
+}
+
+func (p *pahoClient) Disconnect() {
+	p.c.Disconnect(250)
+}
@@ -0,0 +1,231 @@
+// Package mrmqtt connects the Game to dashboards by MQTT.
+//
+// Bridge publishes the events of the Game as JSON messages and keeps the state
+// of the board retained on the broker, so that a dashboard shows it as soon as
+// it subscribes. Commands published to the command topic are sent to the board
+// as if they were typed on the terminal.
+package mrmqtt
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+	"github.com/sirupsen/logrus"
+)
+
+// Topics are the MQTT topics used by the Bridge
+type Topics struct {
+	// State is the retained state of the board updated by every event
+	State string
+	// Moves are the stones put and the moves taken back
+	Moves string
+	// Flips are the stones flipped by each move
+	Flips string
+	// Passes are the passes
+	Passes string
+	// Results is the retained result of the last finished game
+	Results string
+	// Faults are the coils failed to flip stones
+	Faults string
+	// Command is subscribed for commands: new, undo, redo, hint, pause and resume
+	Command string
+}
+
+// NewTopics returns the Topics under prefix such as "magicreversi/state"
+func NewTopics(prefix string) Topics {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	return Topics{
+		State:   prefix + "/state",
+		Moves:   prefix + "/moves",
+		Flips:   prefix + "/flips",
+		Passes:  prefix + "/passes",
+		Results: prefix + "/results",
+		Faults:  prefix + "/faults",
+		Command: prefix + "/command",
+	}
+}
+
+// Sender receives the commands for the Game, which is implemented by mrmiddle.MrMiddle
+type Sender interface {
+	Send(c mrmiddle.Command)
+}
+
+// commands are the payloads of the command topic
+var commands = map[string]mrmiddle.Command{
+	"new":    mrmiddle.NEWGAME,
+	"undo":   mrmiddle.UNDO,
+	"redo":   mrmiddle.REDO,
+	"hint":   mrmiddle.HINT,
+	"pause":  mrmiddle.PAUSE,
+	"resume": mrmiddle.RESUME,
+}
+
+// Bridge is a mrsoft.Observer publishing the events of the Game to the Topics
+type Bridge struct {
+	c      Client
+	topics Topics
+	board  Sender
+	log    logrus.FieldLogger
+	// status of the Game in the state: playing, paused or finished
+	status string
+}
+
+// NewBridge returns a Bridge publishing by c and sending commands to board
+func NewBridge(c Client, topics Topics, board Sender) *Bridge {
+	b := &Bridge{c: c, topics: topics, board: board, status: "playing"}
+	b.SetLogger(logrus.StandardLogger())
+
+	return b
+}
+
+// SetLogger sets the logger of the messages which fail to be published and unknown commands
+func (b *Bridge) SetLogger(l logrus.FieldLogger) {
+	b.log = l.WithField("component", "mrmqtt")
+}
+
+// Listen subscribes to the command topic
+func (b *Bridge) Listen() error {
+	return b.c.Subscribe(b.topics.Command, b.command)
+}
+
+// command sends the command of the payload to the board
+func (b *Bridge) command(topic string, payload []byte) {
+	name := strings.ToLower(strings.TrimSpace(string(payload)))
+	c, ok := commands[name]
+
+	if !ok {
+		b.log.WithFields(logrus.Fields{"topic": topic, "command": name}).Warn("Unknown command")
+		return
+	}
+
+	b.log.WithField("command", name).Info("Command received")
+	b.board.Send(c)
+}
+
+// stateMessage is the payload of the state topic
+type stateMessage struct {
+	// Board is the board diagram of mrsoft.ParseBoard
+	Board  string    `json:"board"`
+	Turn   string    `json:"turn"`
+	Ply    int       `json:"ply"`
+	Black  int       `json:"black"`
+	White  int       `json:"white"`
+	Status string    `json:"status"`
+	Time   time.Time `json:"time"`
+}
+
+// moveMessage is the payload of the moves topic
+type moveMessage struct {
+	Ply    int       `json:"ply"`
+	Player string    `json:"player"`
+	Move   string    `json:"move"`
+	X      int       `json:"x"`
+	Y      int       `json:"y"`
+	Undo   bool      `json:"undo,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// flipMessage is the payload of the flips topic
+type flipMessage struct {
+	Ply    int       `json:"ply"`
+	Player string    `json:"player"`
+	Move   string    `json:"move"`
+	Flips  []string  `json:"flips"`
+	Time   time.Time `json:"time"`
+}
+
+// passMessage is the payload of the passes topic
+type passMessage struct {
+	Ply    int       `json:"ply"`
+	Player string    `json:"player"`
+	Time   time.Time `json:"time"`
+}
+
+// resultMessage is the payload of the results topic
+type resultMessage struct {
+	// Winner is black, white or draw
+	Winner string    `json:"winner"`
+	Black  int       `json:"black"`
+	White  int       `json:"white"`
+	Ply    int       `json:"ply"`
+	Time   time.Time `json:"time"`
+}
+
+// faultMessage is the payload of the faults topic
+type faultMessage struct {
+	Cell  string    `json:"cell"`
+	X     int       `json:"x"`
+	Y     int       `json:"y"`
+	Error string    `json:"error"`
+	Time  time.Time `json:"time"`
+}
+
+// Observe publishes the Event and the state of the board after it
+func (b *Bridge) Observe(e mrsoft.Event) {
+	black, white, _ := e.Position.Count()
+
+	switch e.Kind {
+	case mrsoft.StartEvent, mrsoft.ResumeEvent:
+		b.status = "playing"
+	case mrsoft.PauseEvent:
+		b.status = "paused"
+	case mrsoft.MoveEvent:
+		b.publish(b.topics.Moves, false, moveMessage{Ply: e.Ply, Player: player(e.Player), Move: e.Point.Notation(), X: e.Point[0], Y: e.Point[1], Time: e.Time})
+
+		flips := []string{}
+
+		for _, p := range e.Flips {
+			flips = append(flips, p.Notation())
+		}
+
+		b.publish(b.topics.Flips, false, flipMessage{Ply: e.Ply, Player: player(e.Player), Move: e.Point.Notation(), Flips: flips, Time: e.Time})
+	case mrsoft.UndoEvent:
+		// a pass taken back has no point
+		if e.Point != (mrsoft.Point{}) {
+			b.publish(b.topics.Moves, false, moveMessage{Ply: e.Ply, Player: player(e.Player), Move: e.Point.Notation(), X: e.Point[0], Y: e.Point[1], Undo: true, Time: e.Time})
+		}
+	case mrsoft.PassEvent:
+		b.publish(b.topics.Passes, false, passMessage{Ply: e.Ply, Player: player(e.Player), Time: e.Time})
+	case mrsoft.FinishEvent:
+		b.status = "finished"
+		b.publish(b.topics.Results, true, resultMessage{Winner: player(e.Winner), Black: black, White: white, Ply: e.Ply, Time: e.Time})
+	case mrsoft.FaultEvent:
+		b.publish(b.topics.Faults, false, faultMessage{Cell: e.Point.Notation(), X: e.Point[0], Y: e.Point[1], Error: e.Err.Error(), Time: e.Time})
+	}
+
+	b.publish(b.topics.State, true, stateMessage{
+		Board:  e.Position.Diagram(),
+		Turn:   player(e.Position.Turn()),
+		Ply:    e.Ply,
+		Black:  black,
+		White:  white,
+		Status: b.status,
+		Time:   e.Time,
+	})
+}
+
+// publish publishes the message as JSON. Errors are logged not to stop the Game.
+func (b *Bridge) publish(topic string, retained bool, message interface{}) {
+	payload, err := json.Marshal(message)
+
+	if err == nil {
+		err = b.c.Publish(topic, retained, payload)
+	}
+
+	if err != nil {
+		b.log.WithField("topic", topic).WithError(err).Warn("Failed to publish")
+	}
+}
+
+// player returns the name of the Player in messages
+func player(pl mrsoft.Player) string {
+	if pl == mrsoft.NONE {
+		return "draw"
+	}
+
+	return strings.ToLower(pl.String())
+}
@@ -0,0 +1,304 @@
+package mrmqtt
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// broker is an in-process stand-in of an MQTT broker delivering messages synchronously
+type broker struct {
+	mu       sync.Mutex
+	retained map[string][]byte
+	subs     map[string][]Handler
+}
+
+func newBroker() *broker {
+	return &broker{retained: map[string][]byte{}, subs: map[string][]Handler{}}
+}
+
+// brokerClient is a Client connected to the broker
+type brokerClient struct {
+	b *broker
+}
+
+func (c brokerClient) Publish(topic string, retained bool, payload []byte) error {
+	c.b.mu.Lock()
+
+	if retained {
+		c.b.retained[topic] = payload
+	}
+
+	subs := c.b.subs[topic]
+	c.b.mu.Unlock()
+
+	for _, h := range subs {
+		h(topic, payload)
+	}
+
+	return nil
+}
+
+func (c brokerClient) Subscribe(topic string, h Handler) error {
+	c.b.mu.Lock()
+	c.b.subs[topic] = append(c.b.subs[topic], h)
+	retained, ok := c.b.retained[topic]
+	c.b.mu.Unlock()
+
+	// the retained message is delivered on subscription
+	if ok {
+		h(topic, retained)
+	}
+
+	return nil
+}
+
+func (c brokerClient) Disconnect() {}
+
+// dashboard records the messages of the topics
+type dashboard struct {
+	messages map[string][][]byte
+}
+
+func subscribe(c Client, topics ...string) *dashboard {
+	d := &dashboard{messages: map[string][][]byte{}}
+
+	for _, t := range topics {
+		c.Subscribe(t, func(topic string, payload []byte) {
+			d.messages[topic] = append(d.messages[topic], payload)
+		})
+	}
+
+	return d
+}
+
+// last decodes the last message of the topic into v
+func (d *dashboard) last(t *testing.T, topic string, v interface{}) {
+	ms := d.messages[topic]
+
+	if len(ms) == 0 {
+		t.Fatalf("nothing is published to %s", topic)
+	}
+
+	if err := json.Unmarshal(ms[len(ms)-1], v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// boardMiddleware is a board where stones are put by the inputs and commands are sent after them
+type boardMiddleware struct {
+	inputs [][2]int
+}
+
+func (m *boardMiddleware) Init() error {
+	return nil
+}
+
+func (m *boardMiddleware) GetInput() (int, int, error) {
+	if len(m.inputs) == 0 {
+		return 0, 0, errors.New("End of input")
+	}
+
+	in := m.inputs[0]
+	m.inputs = m.inputs[1:]
+
+	return in[0], in[1], nil
+}
+
+func (m *boardMiddleware) Flip(int, int, mrmiddle.Pole) error {
+	return nil
+}
+
+func (m *boardMiddleware) Send(c mrmiddle.Command) {
+	m.inputs = append(m.inputs, [2]int{int(c), int(c)})
+}
+
+func TestBridge(t *testing.T) {
+	b := newBroker()
+	topics := NewTopics("table/")
+	d := subscribe(brokerClient{b}, topics.State, topics.Moves, topics.Flips)
+
+	// f5 d6, then undo from the dashboard
+	m := &boardMiddleware{inputs: [][2]int{{6, 5}, {4, 6}}}
+
+	g, err := mrsoft.NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bridge := NewBridge(brokerClient{b}, topics, m)
+	g.SetObserver(bridge)
+
+	if err = bridge.Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	brokerClient{b}.Publish(topics.Command, false, []byte("undo\n"))
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	moves := []moveMessage{}
+
+	for _, p := range d.messages[topics.Moves] {
+		var mv moveMessage
+
+		if err = json.Unmarshal(p, &mv); err != nil {
+			t.Fatal(err)
+		}
+
+		moves = append(moves, mv)
+	}
+
+	if len(moves) != 3 || moves[0].Move != "f5" || moves[1].Move != "d6" || moves[1].Player != "white" || !moves[2].Undo || moves[2].Move != "d6" {
+		t.Fatalf("moves are %+v, want f5, d6 and undo d6", moves)
+	}
+
+	// start, f5, d6 and undo
+	turns := []string{"black", "white", "black", "white"}
+
+	if len(d.messages[topics.State]) != len(turns) {
+		t.Fatalf("state is published %d times, want %d", len(d.messages[topics.State]), len(turns))
+	}
+
+	for i, p := range d.messages[topics.State] {
+		var state stateMessage
+
+		if err = json.Unmarshal(p, &state); err != nil {
+			t.Fatal(err)
+		}
+
+		if state.Turn != turns[i] {
+			t.Fatalf("turn of state %d is %s, want %s", i, state.Turn, turns[i])
+		}
+	}
+
+	var flip flipMessage
+	d.last(t, topics.Flips, &flip)
+
+	if flip.Move != "d6" || len(flip.Flips) != 1 || flip.Flips[0] != "d5" {
+		t.Fatalf("flips of d6 are %+v, want d5", flip)
+	}
+
+	// a dashboard subscribing later gets the retained state
+	late := subscribe(brokerClient{b}, topics.State)
+
+	var state stateMessage
+	late.last(t, topics.State, &state)
+
+	pos := mrsoft.InitialPosition()
+
+	if err = pos.Replay([]mrsoft.Point{{6, 5}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Board != pos.Diagram() || state.Turn != "white" || state.Ply != 1 || state.Black != 4 || state.White != 1 || state.Status != "playing" {
+		t.Fatalf("state is %+v, want the position after f5", state)
+	}
+}
+
+func TestBridgeResult(t *testing.T) {
+	// play the first available move until the end of the 4x4 game
+	pos, _ := mrsoft.NewPosition(4)
+	inputs := [][2]int{}
+
+	for !pos.IsFinish() {
+		moves := append(pos.Moves(), mrsoft.Point{})
+
+		if moves[0] != (mrsoft.Point{}) {
+			inputs = append(inputs, [2]int(moves[0]))
+		}
+
+		if err := pos.Replay(moves[:1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := newBroker()
+	topics := NewTopics("table")
+
+	m := &boardMiddleware{inputs: inputs}
+	g, err := mrsoft.NewGame(m, mrsoft.Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.SetObserver(NewBridge(brokerClient{b}, topics, m))
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var result resultMessage
+	subscribe(brokerClient{b}, topics.Results).last(t, topics.Results, &result)
+
+	black, white, _ := pos.Count()
+
+	if result.Winner != player(pos.Winner()) || result.Black != black || result.White != white {
+		t.Fatalf("result is %+v, want %s wins by %d-%d", result, pos.Winner(), black, white)
+	}
+
+	var state stateMessage
+	subscribe(brokerClient{b}, topics.State).last(t, topics.State, &state)
+
+	if state.Status != "finished" {
+		t.Fatalf("status is %s after the game, want finished", state.Status)
+	}
+}
+
+func TestBridgePass(t *testing.T) {
+	b := newBroker()
+	topics := NewTopics("table")
+	d := subscribe(brokerClient{b}, topics.Passes)
+
+	// a2 a3 c4 a1 on 4 x 4 board makes BLACK pass
+	m := &boardMiddleware{inputs: [][2]int{{1, 2}, {1, 3}, {3, 4}, {1, 1}}}
+	g, err := mrsoft.NewGame(m, mrsoft.Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.SetObserver(NewBridge(brokerClient{b}, topics, m))
+	g.Start()
+
+	var pass passMessage
+	d.last(t, topics.Passes, &pass)
+
+	if len(d.messages[topics.Passes]) != 1 || pass.Player != "black" || pass.Ply != 5 {
+		t.Fatalf("passes are %d, the last is %+v, want a pass of black", len(d.messages[topics.Passes]), pass)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	b := newBroker()
+	topics := NewTopics("table")
+	m := &boardMiddleware{}
+
+	if err := NewBridge(brokerClient{b}, topics, m).Listen(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []string{"new", "UNDO", "redo", "hint", "pause", "resume", "unknown"} {
+		brokerClient{b}.Publish(topics.Command, false, []byte(c))
+	}
+
+	want := []mrmiddle.Command{mrmiddle.NEWGAME, mrmiddle.UNDO, mrmiddle.REDO, mrmiddle.HINT, mrmiddle.PAUSE, mrmiddle.RESUME}
+
+	if len(m.inputs) != len(want) {
+		t.Fatalf("%d commands are sent, want %d", len(m.inputs), len(want))
+	}
+
+	for i, c := range want {
+		if m.inputs[i][0] != int(c) {
+			t.Fatalf("command %d is %d, want %d", i, m.inputs[i][0], c)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package mrnboard
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrdb"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// ErrTimeout is returned when the external engine doesn't move within the time limit
+var ErrTimeout = errors.New("External engine timed out")
+
+// ErrExited is returned when the external engine exits while it is asked
+var ErrExited = errors.New("External engine exited")
+
+// Engine is a mrsoft.Engine played by an external program speaking the NBoard protocol
+// such as "edax -nboard". The program is started by the first Move and restarted
+// after it fails. Engine is not safe for concurrent use.
+type Engine struct {
+	// Command is the program and its arguments
+	Command []string
+	// Depth is the search depth told by "set depth"
+	Depth int
+	// TimeLimit is the time to wait for a move
+	TimeLimit time.Duration
+	// Fallback plays instead when the program fails, nil to return the error
+	Fallback mrsoft.Engine
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	// lines written by the program, closed when it exits
+	lines chan string
+	pings int
+}
+
+// NewEngine returns an Engine running the command with default settings
+func NewEngine(command ...string) *Engine {
+	return &Engine{
+		Command:   command,
+		Depth:     10,
+		TimeLimit: 30 * time.Second,
+	}
+}
+
+// Move asks the program for the move of pos. The program is stopped if it fails.
+func (e *Engine) Move(pos mrsoft.Position) (mrsoft.Point, error) {
+	p, err := e.move(pos)
+
+	if err == nil && !isAvailable(&pos, p) {
+		err = fmt.Errorf("External engine played an illegal move %s", p.Notation())
+	}
+
+	if err != nil {
+		e.Close()
+
+		if e.Fallback != nil {
+			fmt.Printf("EXTERNAL ENGINE FAILED: %s\n", err)
+			return e.Fallback.Move(pos)
+		}
+	}
+
+	return p, err
+}
+
+// isAvailable reports whether p is an available point of pos
+func isAvailable(pos *mrsoft.Position, p mrsoft.Point) bool {
+	for _, m := range pos.Moves() {
+		if m == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// move sets the game of pos and waits for the reply of "go"
+func (e *Engine) move(pos mrsoft.Position) (p mrsoft.Point, err error) {
+	if e.cmd == nil {
+		if err = e.start(); err != nil {
+			return
+		}
+	}
+
+	game := &bytes.Buffer{}
+	r := &mrsoft.Record{Size: pos.Size(), Rules: pos.Rules(), Start: pos.Diagram()}
+
+	if err = mrdb.WriteGGF(game, &mrdb.Game{Black: "black", White: "white", Record: r}); err != nil {
+		return
+	}
+
+	deadline := time.After(e.TimeLimit)
+
+	// wait until the game is set
+	e.pings++
+
+	if err = e.send("set game %s", strings.TrimSpace(game.String())); err != nil {
+		return
+	}
+
+	if err = e.send("ping %d", e.pings); err != nil {
+		return
+	}
+
+	pong := fmt.Sprintf("pong %d", e.pings)
+
+	// "pong 1" must not be taken for "pong 10"
+	if _, err = e.wait(func(line string) bool { return strings.TrimSpace(line) == pong }, deadline); err != nil {
+		return
+	}
+
+	if err = e.send("go"); err != nil {
+		return
+	}
+
+	line, err := e.wait(func(line string) bool { return strings.HasPrefix(line, "===") }, deadline)
+
+	if err != nil {
+		return
+	}
+
+	// "=== F5/1.00/0.5"
+	move := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(line, "===")), "/", 2)[0]
+
+	return mrsoft.ParsePoint(move)
+}
+
+// start starts the program
+func (e *Engine) start() (err error) {
+	if len(e.Command) == 0 {
+		return errors.New("No external engine is given")
+	}
+
+	cmd := exec.Command(e.Command[0], e.Command[1:]...)
+
+	if e.stdin, err = cmd.StdinPipe(); err != nil {
+		return
+	}
+
+	stdout, err := cmd.StdoutPipe()
+
+	if err != nil {
+		return
+	}
+
+	if err = cmd.Start(); err != nil {
+		return fmt.Errorf("Failed to start external engine: %s", err)
+	}
+
+	lines := make(chan string, 16)
+
+	go func() {
+		s := bufio.NewScanner(stdout)
+
+		for s.Scan() {
+			lines <- s.Text()
+		}
+
+		close(lines)
+		cmd.Wait()
+	}()
+
+	e.cmd, e.lines = cmd, lines
+
+	if err = e.send("nboard 2"); err != nil {
+		return
+	}
+
+	return e.send("set depth %d", e.Depth)
+}
+
+// send writes a command to the program
+func (e *Engine) send(format string, args ...interface{}) error {
+	if _, err := fmt.Fprintf(e.stdin, format+"\n", args...); err != nil {
+		return ErrExited
+	}
+
+	return nil
+}
+
+// wait returns the first line which matches, skipping other lines
+func (e *Engine) wait(match func(line string) bool, deadline <-chan time.Time) (string, error) {
+	for {
+		select {
+		case line, ok := <-e.lines:
+			if !ok {
+				return "", ErrExited
+			}
+
+			if match(line) {
+				return line, nil
+			}
+		case <-deadline:
+			return "", ErrTimeout
+		}
+	}
+}
+
+// Close stops the program
+func (e *Engine) Close() error {
+	if e.cmd == nil {
+		return nil
+	}
+
+	e.stdin.Close()
+	err := e.cmd.Process.Kill()
+
+	// drain the lines so that the reader exits
+	go func(lines chan string) {
+		for range lines {
+		}
+	}(e.lines)
+
+	e.cmd, e.stdin, e.lines = nil, nil, nil
+
+	return err
+}
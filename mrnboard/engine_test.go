@@ -0,0 +1,135 @@
+package mrnboard
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrdb"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// fakeEngineEnv makes the test binary a fake engine of the mode
+const fakeEngineEnv = "MRNBOARD_FAKE_ENGINE"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(fakeEngineEnv); mode != "" {
+		fakeEngine(mode)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+// fakeEngine plays the last available move of the game.
+// It crashes on "go" in "crash" mode and never answers in "slow" mode.
+// In "stale" mode it answers "ping N" by "pong N0" and the first available move before "pong N".
+func fakeEngine(mode string) {
+	var pos mrsoft.Position
+
+	s := bufio.NewScanner(os.Stdin)
+
+	for s.Scan() {
+		line := s.Text()
+
+		switch {
+		case strings.HasPrefix(line, "ping ") && mode == "stale":
+			fmt.Println("pong" + strings.TrimPrefix(line, "ping") + "0")
+			fmt.Printf("=== %s\n", strings.ToUpper(pos.Moves()[0].Notation()))
+			fmt.Println("pong" + strings.TrimPrefix(line, "ping"))
+		case strings.HasPrefix(line, "ping "):
+			fmt.Println("pong" + strings.TrimPrefix(line, "ping"))
+		case strings.HasPrefix(line, "set game "):
+			games, err := mrdb.ReadGGF(strings.NewReader(strings.TrimPrefix(line, "set game ")))
+
+			if err != nil {
+				os.Exit(2)
+			}
+
+			pos, _ = games[0].Record.Position()
+		case line == "go" && mode == "crash":
+			os.Exit(1)
+		case line == "go" && mode == "slow":
+			time.Sleep(time.Minute)
+		case line == "go":
+			moves := pos.Moves()
+			fmt.Println("status thinking")
+			fmt.Printf("=== %s/0.00/0.1\n", strings.ToUpper(moves[len(moves)-1].Notation()))
+		}
+	}
+}
+
+// newFakeEngine returns an Engine running the fake engine of the mode
+func newFakeEngine(t *testing.T, mode string) *Engine {
+	t.Helper()
+	t.Setenv(fakeEngineEnv, mode)
+
+	e := NewEngine(os.Args[0])
+	e.TimeLimit = 2 * time.Second
+
+	return e
+}
+
+func TestEngine(t *testing.T) {
+	// lines before the pong of the game are not taken for the answers
+	for _, mode := range []string{"normal", "stale"} {
+		e := newFakeEngine(t, mode)
+
+		pos, _ := mrsoft.ParsePosition("standard 8 f5d6")
+
+		for i := 0; i < 2; i++ {
+			p, err := e.Move(pos)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			moves := pos.Moves()
+
+			if p != moves[len(moves)-1] {
+				t.Fatalf("Move of %s engine returns %s, want %s", mode, p.Notation(), moves[len(moves)-1].Notation())
+			}
+
+			pos.Replay([]mrsoft.Point{p})
+		}
+
+		e.Close()
+	}
+}
+
+func TestEngineFailure(t *testing.T) {
+	pos := mrsoft.InitialPosition()
+
+	for _, c := range []struct {
+		mode string
+		err  error
+	}{
+		{"crash", ErrExited},
+		{"slow", ErrTimeout},
+	} {
+		e := newFakeEngine(t, c.mode)
+		e.TimeLimit = 500 * time.Millisecond
+
+		if _, err := e.Move(pos); err != c.err {
+			t.Fatalf("Move of %s engine returns %v, want %v", c.mode, err, c.err)
+		}
+
+		// the Fallback plays instead
+		e.Fallback = mrsoft.NewSearcher()
+
+		if p, err := e.Move(pos); err != nil || p == (mrsoft.Point{}) {
+			t.Fatalf("Move with Fallback returns %v, %v", p, err)
+		}
+
+		e.Close()
+	}
+
+	e := NewEngine("/nonexistent/engine")
+
+	if _, err := e.Move(pos); err == nil {
+		t.Fatal("Move of a missing engine returns no error")
+	}
+}
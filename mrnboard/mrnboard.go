@@ -0,0 +1,230 @@
+// Package mrnboard speaks the NBoard protocol with Othello GUIs and external engines.
+//
+// Server lets a GUI drive the board. The GUI sets the game and sends the moves
+// of its players, which are put on the physical board. When the GUI asks for
+// a move by "go", the server waits for the stone put on the board and reports it back.
+// Engine plays by an external program such as Edax.
+package mrnboard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/69guitar1015/MagicReversi/mrdb"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// Name is the name of the engine told to the GUI
+const Name = "MagicReversi"
+
+// Server serves the Game to a GUI
+type Server struct {
+	g *mrsoft.Game
+	w io.Writer
+	// depth set by the GUI, reported with hints
+	depth int
+	// move reported by "go", which the GUI may send back by "move"
+	pending *mrsoft.Point
+}
+
+// NewServer returns a Server of the Game
+func NewServer(g *mrsoft.Game) *Server {
+	return &Server{g: g, depth: 1}
+}
+
+// Serve reads commands from r and writes responses to w until r ends
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.w = w
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1024*1024)
+
+	for sc.Scan() {
+		if err := s.handle(strings.TrimSpace(sc.Text())); err != nil {
+			return err
+		}
+	}
+
+	return sc.Err()
+}
+
+// handle runs a command. Errors of the Game are shown in the status of the GUI,
+// and only errors of writing to the GUI are returned.
+func (s *Server) handle(line string) error {
+	fields := strings.Fields(line)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var err error
+
+	switch fields[0] {
+	case "nboard":
+		return s.send("set myname %s", Name)
+	case "ping":
+		return s.send("pong %s", strings.Join(fields[1:], " "))
+	case "learn":
+		return s.send("learned")
+	case "set":
+		err = s.set(fields[1:], line)
+	case "move":
+		err = s.move(fields[1:])
+	case "hint":
+		err = s.hint(fields[1:])
+	case "go":
+		err = s.goMove()
+	default:
+		// unknown commands are ignored as the protocol says
+		return nil
+	}
+
+	if _, ok := err.(writeError); ok {
+		return err
+	}
+
+	if err != nil {
+		return s.send("status ERROR: %s", err)
+	}
+
+	return nil
+}
+
+// writeError is an error of writing to the GUI
+type writeError struct {
+	error
+}
+
+// send writes a line to the GUI
+func (s *Server) send(format string, args ...interface{}) error {
+	if _, err := fmt.Fprintf(s.w, format+"\n", args...); err != nil {
+		return writeError{err}
+	}
+
+	return nil
+}
+
+// set handles "set depth", "set game" and ignores other settings
+func (s *Server) set(args []string, line string) (err error) {
+	if len(args) == 0 {
+		return nil
+	}
+
+	switch args[0] {
+	case "depth":
+		if len(args) < 2 {
+			return fmt.Errorf("Invalid depth: %q", line)
+		}
+
+		s.depth, err = strconv.Atoi(args[1])
+	case "game":
+		games, err := mrdb.ReadGGF(strings.NewReader(line[strings.Index(line, "game")+len("game"):]))
+
+		if err != nil {
+			return err
+		}
+
+		if len(games) != 1 {
+			return fmt.Errorf("%d games are given", len(games))
+		}
+
+		s.pending = nil
+
+		return s.g.Load(games[0].Record)
+	}
+
+	return
+}
+
+// move plays the move of a player of the GUI such as "F5/1.00/0.5"
+func (s *Server) move(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("No move is given")
+	}
+
+	p, err := mrsoft.ParsePoint(strings.SplitN(args[0], "/", 2)[0])
+
+	if err != nil {
+		return err
+	}
+
+	// the move of "go" is already on the board
+	if s.pending != nil {
+		pending := *s.pending
+		s.pending = nil
+
+		if p == pending {
+			return nil
+		}
+	}
+
+	if err = s.g.Play(p); err != nil {
+		return err
+	}
+
+	return s.send("status")
+}
+
+// hint reports the evaluations of n best moves.
+// NBoard shows evaluations in discs, so the points of the Searcher are estimated in discs.
+func (s *Server) hint(args []string) error {
+	n := 1
+
+	if len(args) != 0 {
+		if v, err := strconv.Atoi(args[0]); err == nil {
+			n = v
+		}
+	}
+
+	if err := s.send("status thinking"); err != nil {
+		return err
+	}
+
+	scores, err := s.g.Hint()
+
+	if err != nil {
+		return err
+	}
+
+	for i, ms := range scores {
+		if i >= n {
+			break
+		}
+
+		discs, ok := ms.Discs()
+
+		if !ok {
+			return s.send("status no hint in discs by the analyzer")
+		}
+
+		if err = s.send("search %s %.2f 0 %d", strings.ToUpper(ms.Move.Notation()), discs, s.depth); err != nil {
+			return err
+		}
+	}
+
+	return s.send("status")
+}
+
+// goMove waits for the stone put on the board and reports it
+func (s *Server) goMove() error {
+	if err := s.send("status waiting for a stone on the board"); err != nil {
+		return err
+	}
+
+	p, err := s.g.Wait()
+
+	if err != nil {
+		return err
+	}
+
+	s.pending = &p
+
+	if err = s.send("=== %s", strings.ToUpper(p.Notation())); err != nil {
+		return err
+	}
+
+	return s.send("status")
+}
@@ -0,0 +1,151 @@
+package mrnboard
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// tableMiddleware is a board where stones are put by Place or by the inputs
+type tableMiddleware struct {
+	inputs [][2]int
+	placed [][2]int
+}
+
+func (m *tableMiddleware) Init() error {
+	return nil
+}
+
+func (m *tableMiddleware) GetInput() (int, int, error) {
+	if len(m.inputs) == 0 {
+		return 0, 0, errors.New("End of input")
+	}
+
+	in := m.inputs[0]
+	m.inputs = m.inputs[1:]
+
+	return in[0], in[1], nil
+}
+
+func (m *tableMiddleware) Flip(int, int, mrmiddle.Pole) error {
+	return nil
+}
+
+func (m *tableMiddleware) Place(x, y int, _ mrmiddle.Pole) error {
+	m.placed = append(m.placed, [2]int{x, y})
+	return nil
+}
+
+func TestServe(t *testing.T) {
+	m := &tableMiddleware{
+		// an illegal stone on a1 is rejected and lifted before f6
+		inputs: [][2]int{{1, 1}, {int(mrmiddle.REMOVE), int(mrmiddle.REMOVE)}, {6, 6}},
+	}
+
+	g, err := mrsoft.NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commands := []string{
+		"nboard 2",
+		"set depth 3",
+		"set game (;GM[Othello]PC[NBoard]DT[2026.10.19_10:00:00.UTC]PB[gui]PW[board]RE[?]TI[5:00]TY[8]BO[8 ---------------------------O*------*O--------------------------- *]B[F5//1.2];)",
+		"ping 1",
+		"go",
+		"move F6/0.00/1",
+		"move E6",
+		"hint 2",
+		"move A1",
+		"ping 2",
+	}
+
+	out := &bytes.Buffer{}
+
+	if err = NewServer(g).Serve(strings.NewReader(strings.Join(commands, "\n")), out); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+
+	for _, want := range []string{"set myname MagicReversi", "pong 1", "=== F6", "pong 2"} {
+		found := false
+
+		for _, l := range lines {
+			found = found || l == want
+		}
+
+		if !found {
+			t.Fatalf("%q is not sent in\n%s", want, out)
+		}
+	}
+
+	searches, errs := 0, 0
+
+	for _, l := range lines {
+		if strings.HasPrefix(l, "search ") && strings.HasSuffix(l, " 0 3") {
+			searches++
+
+			// the points of the Searcher are estimated in discs
+			if v, err := strconv.ParseFloat(strings.Fields(l)[2], 64); err != nil || v < -64 || 64 < v {
+				t.Fatalf("hint %q is not in discs", l)
+			}
+		}
+
+		if strings.HasPrefix(l, "status ERROR: ") {
+			errs++
+		}
+	}
+
+	if searches != 2 || errs != 1 {
+		t.Fatalf("%d hints and %d errors are sent in\n%s", searches, errs, out)
+	}
+
+	// moves of the GUI are put by the middleware
+	if len(m.placed) != 2 || m.placed[0] != [2]int{6, 5} || m.placed[1] != [2]int{5, 6} {
+		t.Fatalf("stones are placed on %v", m.placed)
+	}
+
+	want, _ := mrsoft.ParsePosition("standard 8 f5f6e6")
+	pos := g.Position()
+
+	if pos.Diagram() != want.Diagram() || g.Illegals(mrsoft.WHITE) != 1 {
+		t.Fatalf("position is %s with %d illegal moves", pos.Diagram(), g.Illegals(mrsoft.WHITE))
+	}
+}
+
+func TestHint(t *testing.T) {
+	g, err := mrsoft.NewGame(&tableMiddleware{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10 blanks, BLACK to move
+	board := strings.NewReplacer("X", "*").Replace("------XXX-OOOXXXOOOOXXOXOOXOXXOXOOOXOOOXOOOOXOOXOOOXOOXXO-OOOO--")
+
+	commands := []string{
+		"nboard 2",
+		"set game (;GM[Othello]PC[NBoard]PB[gui]PW[board]RE[?]TY[8]BO[8 " + board + " *];)",
+		"hint 3",
+	}
+
+	out := &bytes.Buffer{}
+
+	if err = NewServer(g).Serve(strings.NewReader(strings.Join(commands, "\n")), out); err != nil {
+		t.Fatal(err)
+	}
+
+	// the final disc differentials read out by the Solver
+	want := "search D1 16.00 0 1\nsearch B1 14.00 0 1\nsearch E1 6.00 0 1\n"
+
+	if !strings.Contains(out.String(), want) {
+		t.Fatalf("hints are not %q in\n%s", want, out)
+	}
+}
@@ -0,0 +1,264 @@
+package mrsoft
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Grade represents the quality of a move
+type Grade int
+
+const (
+	// GOOD is a move which lost less than the mistake threshold
+	GOOD Grade = iota
+	// MISTAKE is a move which lost more than the mistake threshold
+	MISTAKE
+	// BLUNDER is a move which lost more than the blunder threshold
+	BLUNDER
+)
+
+func (g Grade) String() string {
+	switch g {
+	case GOOD:
+		return "GOOD"
+	case MISTAKE:
+		return "MISTAKE"
+	case BLUNDER:
+		return "BLUNDER"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MoveAnalysis is the analysis of a played move.
+// Scores are from the view of Player, in discs when Exact.
+type MoveAnalysis struct {
+	Move   Point  `json:"move"`
+	Player Player `json:"player"`
+	// Score is the score of the played move
+	Score int `json:"score"`
+	// BestMove is the best alternative and BestScore is its score
+	BestMove  Point `json:"best_move"`
+	BestScore int   `json:"best_score"`
+	// Exact reports whether the scores are solved to the end
+	Exact bool `json:"exact"`
+	// Unit is the unit of the scores, which differs between the Analyzer and the Solver
+	Unit  ScoreUnit `json:"unit"`
+	Grade Grade     `json:"grade"`
+}
+
+// Loss returns how much the move lost compared with the best move
+func (m MoveAnalysis) Loss() int {
+	return m.BestScore - m.Score
+}
+
+// Analysis is the result of the post-game analysis
+type Analysis struct {
+	Moves []MoveAnalysis `json:"moves"`
+}
+
+// Reviewer analyzes finished games move by move
+type Reviewer struct {
+	// Analyzer evaluates moves until the Solver can read the game out
+	Analyzer Analyzer
+	Solver   *Solver
+	// Mistake and Blunder are the thresholds of loss in the unit of Analyzer
+	Mistake, Blunder int
+	// ExactMistake and ExactBlunder are the thresholds of loss in discs
+	ExactMistake, ExactBlunder int
+}
+
+// NewReviewer returns a Reviewer with default settings
+func NewReviewer() *Reviewer {
+	return &Reviewer{
+		Analyzer:     NewSearcher(),
+		Solver:       NewSolver(),
+		Mistake:      30,
+		Blunder:      80,
+		ExactMistake: 4,
+		ExactBlunder: 10,
+	}
+}
+
+// Review replays records from start and analyzes each move
+func (r *Reviewer) Review(start Position, records []PutRecord) (*Analysis, error) {
+	a := &Analysis{}
+	pos := start
+
+	for i, record := range records {
+		if pos.crr != record.player {
+			pos.pass()
+		}
+
+		// a pass is forced and kept only to align with the records
+		if record.point.equal(passPoint) {
+			a.Moves = append(a.Moves, MoveAnalysis{Move: passPoint, Player: record.player})
+			pos.pass()
+			continue
+		}
+
+		ma, err := r.analyze(pos, record.point)
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to analyze move %d: %s", i+1, err)
+		}
+
+		a.Moves = append(a.Moves, ma)
+		pos.play(record.point)
+	}
+
+	return a, nil
+}
+
+// analyze evaluates the position before and after the move p
+func (r *Reviewer) analyze(pos Position, p Point) (ma MoveAnalysis, err error) {
+	ma = MoveAnalysis{Move: p, Player: pos.crr}
+
+	var scores []MoveScore
+
+	if _, _, blank := pos.Count(); blank <= r.Solver.Empties {
+		ma.Exact = true
+		scores, err = r.Solver.Analyze(pos)
+	} else {
+		scores, err = r.Analyzer.Analyze(pos)
+	}
+
+	if err != nil {
+		return
+	}
+
+	found := false
+
+	for _, s := range scores {
+		if s.Move.equal(p) {
+			ma.Score, found = s.Score, true
+		}
+	}
+
+	if !found {
+		return ma, fmt.Errorf("(%d, %d) is not available", p[0], p[1])
+	}
+
+	ma.BestMove, ma.BestScore, ma.Unit = scores[0].Move, scores[0].Score, scores[0].Unit
+
+	mistake, blunder := r.Mistake, r.Blunder
+
+	if ma.Exact {
+		mistake, blunder = r.ExactMistake, r.ExactBlunder
+	}
+
+	switch {
+	case ma.Loss() >= blunder:
+		ma.Grade = BLUNDER
+	case ma.Loss() >= mistake:
+		ma.Grade = MISTAKE
+	}
+
+	return
+}
+
+// Mistakes returns the moves graded as mistakes or blunders
+func (a *Analysis) Mistakes() (moves []MoveAnalysis) {
+	for _, m := range a.Moves {
+		if m.Grade != GOOD {
+			moves = append(moves, m)
+		}
+	}
+
+	return
+}
+
+// WriteGraph writes the evaluation graph of the moves from the view of black.
+// Bars are scaled separately for each unit of scores.
+func (a *Analysis) WriteGraph(w io.Writer) {
+	const width = 20
+
+	max := map[ScoreUnit]int{}
+
+	for _, m := range a.Moves {
+		if s := abs(m.blackScore()); s > max[m.Unit] {
+			max[m.Unit] = s
+		}
+	}
+
+	for i, m := range a.Moves {
+		if m.Move.equal(passPoint) {
+			fmt.Fprintf(w, "[%2d]\t%s\n", i+1, m.Move.Notation())
+			continue
+		}
+
+		s := m.blackScore()
+		n := 0
+
+		if max[m.Unit] != 0 {
+			n = abs(s) * width / max[m.Unit]
+		}
+
+		left, right := strings.Repeat(" ", width), strings.Repeat(" ", width)
+
+		if s < 0 {
+			left = strings.Repeat(" ", width-n) + strings.Repeat("#", n)
+		} else {
+			right = strings.Repeat("#", n) + strings.Repeat(" ", width-n)
+		}
+
+		mark := ""
+		if m.Exact {
+			mark = "*"
+		}
+
+		fmt.Fprintf(w, "[%2d]\t%s\t%+6d%s\t%s|%s\t%s\n", i+1, m.Move.Notation(), s, mark, left, right, m.Grade)
+	}
+}
+
+// blackScore returns the score of the played move from the view of black
+func (m MoveAnalysis) blackScore() int {
+	if m.Player == WHITE {
+		return -m.Score
+	}
+
+	return m.Score
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+// SetReviewer enables the post-game analysis by the Reviewer. nil disables it.
+func (g *Game) SetReviewer(r *Reviewer) {
+	g.reviewer = r
+}
+
+// review analyzes the finished Game
+func (g *Game) review() (err error) {
+	if g.reviewer == nil {
+		return
+	}
+
+	fmt.Println("Analyzing...")
+
+	g.analysis, err = g.reviewer.Review(g.start, g.history)
+
+	return
+}
+
+// printAnalysis prints the mistakes and the evaluation graph
+func (g *Game) printAnalysis() {
+	if g.analysis == nil {
+		return
+	}
+
+	fmt.Printf("\n# MISTAKES\n")
+	for _, m := range g.analysis.Mistakes() {
+		fmt.Printf("%s\t%s (%d, %d)\tLOST %d %s, BETTER: (%d, %d)\n", m.Grade, m.Player, m.Move[0], m.Move[1], m.Loss(), strings.ToUpper(m.Unit.String()), m.BestMove[0], m.BestMove[1])
+	}
+
+	fmt.Printf("\n# EVALUATION (+: BLACK, *: EXACT)\n")
+	g.analysis.WriteGraph(os.Stdout)
+}
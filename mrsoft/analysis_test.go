@@ -0,0 +1,82 @@
+package mrsoft
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// firstEngine plays the first available point
+type firstEngine struct{}
+
+func (firstEngine) Move(pos Position) (Point, error) {
+	return pos.Moves()[0], nil
+}
+
+func TestReview(t *testing.T) {
+	m := &placingMiddleware{}
+	g, err := NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewSearcher()
+	s.Depth = 2
+
+	g.SetEngine(BLACK, firstEngine{})
+	g.SetEngine(WHITE, s)
+
+	r := NewReviewer()
+	r.Analyzer = s
+	r.Solver.Empties = 8
+	g.SetReviewer(r)
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	a := g.Record().Analysis
+
+	if a == nil {
+		t.Fatal("Record has no analysis")
+	}
+
+	if len(a.Moves) != len(g.history) {
+		t.Fatalf("Analysis has %d moves, want %d", len(a.Moves), len(g.history))
+	}
+
+	for i, ma := range a.Moves {
+		if !ma.Move.equal(g.history[i].point) || ma.Player != g.history[i].player {
+			t.Fatalf("move %d is analyzed as %v by %s", i+1, ma.Move, ma.Player)
+		}
+
+		if ma.Loss() < 0 {
+			t.Fatalf("move %d is better than the best move", i+1)
+		}
+
+		mistake := r.Mistake
+		if ma.Exact {
+			mistake = r.ExactMistake
+		}
+
+		if (ma.Grade != GOOD) != (ma.Loss() >= mistake) {
+			t.Fatalf("move %d losing %d is graded as %s", i+1, ma.Loss(), ma.Grade)
+		}
+	}
+
+	if !a.Moves[len(a.Moves)-1].Exact {
+		t.Fatal("The last move is not solved exactly")
+	}
+
+	if len(a.Mistakes()) == 0 {
+		t.Fatal("No mistake is found in the moves of firstEngine")
+	}
+
+	buf := &bytes.Buffer{}
+	a.WriteGraph(buf)
+
+	if n := strings.Count(buf.String(), "\n"); n != len(a.Moves) {
+		t.Fatalf("graph has %d lines, want %d", n, len(a.Moves))
+	}
+}
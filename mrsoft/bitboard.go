@@ -0,0 +1,131 @@
+package mrsoft
+
+import "math/bits"
+
+// bitboard represents stones of boards up to 8 x 8 by bit sets.
+// The bit of (x, y) is 8 * (y - 1) + (x - 1).
+type bitboard struct {
+	own, opp uint64
+	// cells of the board
+	mask uint64
+}
+
+const (
+	notFileA = 0xfefefefefefefefe
+	notFileH = 0x7f7f7f7f7f7f7f7f
+)
+
+// bitShifts move all bits one cell toward each of 8 directions
+var bitShifts = [8]func(uint64) uint64{
+	func(b uint64) uint64 { return b << 1 & notFileA },
+	func(b uint64) uint64 { return b >> 1 & notFileH },
+	func(b uint64) uint64 { return b << 8 },
+	func(b uint64) uint64 { return b >> 8 },
+	func(b uint64) uint64 { return b << 9 & notFileA },
+	func(b uint64) uint64 { return b << 7 & notFileH },
+	func(b uint64) uint64 { return b >> 7 & notFileA },
+	func(b uint64) uint64 { return b >> 9 & notFileH },
+}
+
+// newBitboard returns the bitboard of pos from the view of the Player to move
+func newBitboard(pos *Position) (bb bitboard) {
+	n := pos.b.size()
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			bit := uint64(1) << uint(8*(y-1)+x-1)
+			bb.mask |= bit
+
+			switch pos.b[y][x] {
+			case pos.crr.color():
+				bb.own |= bit
+			case pos.crr.enemy().color():
+				bb.opp |= bit
+			}
+		}
+	}
+
+	return
+}
+
+// moves returns the bit set of available cells
+func (bb *bitboard) moves() (moves uint64) {
+	empty := bb.mask &^ (bb.own | bb.opp)
+
+	for _, shift := range bitShifts {
+		t := shift(bb.own) & bb.opp
+
+		for i := 0; i < 5; i++ {
+			t |= shift(t) & bb.opp
+		}
+
+		moves |= shift(t) & empty
+	}
+
+	return
+}
+
+// play puts a stone on the cell of bit m and passes the turn
+func (bb *bitboard) play(m uint64) {
+	var flips uint64
+
+	for _, shift := range bitShifts {
+		var f uint64
+		t := shift(m)
+
+		for t&bb.opp != 0 {
+			f |= t
+			t = shift(t)
+		}
+
+		if t&bb.own != 0 {
+			flips |= f
+		}
+	}
+
+	bb.own, bb.opp = bb.opp&^flips, bb.own|flips|m
+}
+
+// pass passes the turn
+func (bb *bitboard) pass() {
+	bb.own, bb.opp = bb.opp, bb.own
+}
+
+// perft counts leaves of the bitboard at depth
+func (bb bitboard) perft(depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+
+	moves := bb.moves()
+
+	if moves == 0 {
+		bb.pass()
+
+		if bb.moves() == 0 {
+			return 1
+		}
+
+		return bb.perft(depth - 1)
+	}
+
+	// leaves are the moves themselves
+	if depth == 1 {
+		return uint64(bits.OnesCount64(moves))
+	}
+
+	var nodes uint64
+
+	for ; moves != 0; moves &= moves - 1 {
+		child := bb
+		child.play(moves & -moves)
+		nodes += child.perft(depth - 1)
+	}
+
+	return nodes
+}
+
+// perftBitboard counts leaves by the bitboard
+func perftBitboard(pos Position, depth int) uint64 {
+	return newBitboard(&pos).perft(depth)
+}
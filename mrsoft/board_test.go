@@ -0,0 +1,110 @@
+package mrsoft
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+)
+
+// sizedMiddleware supports only n x n board
+type sizedMiddleware struct {
+	placingMiddleware
+	n int
+}
+
+func (m *sizedMiddleware) Flip(x int, y int, pd mrmiddle.Pole) (err error) {
+	if x < 1 || m.n < x || y < 1 || m.n < y {
+		return errors.New("Can't put stones there")
+	}
+
+	return
+}
+
+func (m *sizedMiddleware) Size() int {
+	return m.n
+}
+
+func TestNewBoard(t *testing.T) {
+	for _, n := range []int{4, 6, 8, 10} {
+		b, err := newBoard(n)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if b.size() != n {
+			t.Fatalf("size of %dx%d board is %d", n, n, b.size())
+		}
+
+		pos := Position{b: b, crr: BLACK}
+		black, white, blank := pos.Count()
+
+		if black != 2 || white != 2 || blank != n*n-4 {
+			t.Fatalf("%dx%d board has %d black, %d white and %d blank", n, n, black, white, blank)
+		}
+
+		if len(pos.Moves()) != 4 {
+			t.Fatalf("%dx%d board has %d available points, want 4", n, n, len(pos.Moves()))
+		}
+	}
+
+	for _, n := range []int{2, 5, 12} {
+		if _, err := newBoard(n); err == nil {
+			t.Fatalf("newBoard(%d) succeeded", n)
+		}
+	}
+}
+
+func TestGameSizes(t *testing.T) {
+	for _, n := range []int{4, 6, 10} {
+		m := &sizedMiddleware{n: n}
+		g, err := NewGame(m, Size(n))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, p := range []Player{BLACK, WHITE} {
+			s := NewSearcher()
+			s.Depth = 1
+			g.SetEngine(p, s)
+		}
+
+		if err = g.Start(); err != nil {
+			t.Fatalf("%dx%d: %s", n, n, err)
+		}
+
+		if err = g.put(Point{n + 1, 1}); err == nil {
+			t.Fatalf("%dx%d: put out of the board succeeded", n, n)
+		}
+	}
+}
+
+func TestGameRejectsSize(t *testing.T) {
+	if _, err := NewGame(&sizedMiddleware{n: 8}, Size(6)); err == nil {
+		t.Fatal("8x8 middleware accepted 6x6 board")
+	}
+
+	if _, err := NewGame(&sizedMiddleware{n: 8}, Size(7)); err == nil {
+		t.Fatal("7x7 board is accepted")
+	}
+}
+
+func TestParsePoint(t *testing.T) {
+	moves, err := ParseMoves("a1 j10f5")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(moves) != 3 || !moves[1].equal(Point{10, 10}) || FormatMoves(moves) != "a1j10f5" {
+		t.Fatalf("ParseMoves returned %v", moves)
+	}
+
+	for _, s := range []string{"k1", "a0", "a11", "f+5", "5f"} {
+		if _, err := ParsePoint(s); err == nil {
+			t.Fatalf("ParsePoint(%q) succeeded", s)
+		}
+	}
+}
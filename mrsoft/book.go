@@ -0,0 +1,381 @@
+package mrsoft
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// defaultBook is the opening book used when no book file is given
+const defaultBook = `# moves score name
+f5d6 0 Perpendicular
+f5f4 3 Parallel
+f5f6 0 Diagonal
+f5d6c3d3c4 0 Tiger
+f5d6c3d3c4f4c5b3c2 0 Stephenson
+f5d6c3d3c4f4f6f3e6e7 0 No-Kung
+f5d6c5 0 Cow
+f5d6c5f4e3f6g5e6e7 0 Rose
+f5f6e6f4c3 0 Buffalo
+f5f6e6f4g5 0 Heath
+f5f6e6f4e3 0 Rabbit
+`
+
+// BookLine is a move sequence of the opening book
+type BookLine struct {
+	Moves []Point
+	// Score is the evaluation of the last position for black in discs
+	Score int
+	// Name is the name of the opening, may be empty
+	Name string
+}
+
+type bookEntry struct {
+	name string
+	// sum of scores and the number of lines ending at this position
+	sum, n int
+}
+
+// bookValue is the memoized result of Book.value
+type bookValue struct {
+	v  int
+	ok bool
+}
+
+// Book is an opening book which knows positions reached by move sequences.
+// Positions are looked up under all 8 symmetries of the board.
+// A Book may be used by Games running concurrently once it is built.
+type Book struct {
+	positions map[positionKey]*bookEntry
+	lines     []BookLine
+
+	// mu guards values, which caches the value of positions reached by transpositions
+	// and is cleared when a line is added
+	mu     sync.Mutex
+	values map[positionKey]bookValue
+}
+
+// NewBook returns an empty Book
+func NewBook() *Book {
+	return &Book{positions: map[positionKey]*bookEntry{}}
+}
+
+// DefaultBook returns the Book of well known named openings
+func DefaultBook() *Book {
+	b, err := ReadBook(strings.NewReader(defaultBook))
+
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// LoadBook reads the book file at path
+func LoadBook(path string) (*Book, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return ReadBook(f)
+}
+
+// ReadBook reads a Book whose lines are "<moves> <score> [name]".
+// Empty lines and lines beginning with "#" are ignored.
+func ReadBook(r io.Reader) (*Book, error) {
+	b := NewBook()
+	s := bufio.NewScanner(r)
+
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 3)
+
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("Book line %d: score is missing", n)
+		}
+
+		moves, err := ParseMoves(fields[0])
+
+		if err != nil {
+			return nil, fmt.Errorf("Book line %d: %s", n, err)
+		}
+
+		score, err := strconv.Atoi(fields[1])
+
+		if err != nil {
+			return nil, fmt.Errorf("Book line %d: %s", n, err)
+		}
+
+		l := BookLine{Moves: moves, Score: score}
+
+		if len(fields) == 3 {
+			l.Name = strings.TrimSpace(fields[2])
+		}
+
+		if err = b.Add(l); err != nil {
+			return nil, fmt.Errorf("Book line %d: %s", n, err)
+		}
+	}
+
+	return b, s.Err()
+}
+
+// Write writes the Book in the format of ReadBook
+func (b *Book) Write(w io.Writer) error {
+	for _, l := range b.lines {
+		s := fmt.Sprintf("%s %d", FormatMoves(l.Moves), l.Score)
+
+		if l.Name != "" {
+			s += " " + l.Name
+		}
+
+		if _, err := fmt.Fprintln(w, s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Add adds the line to the Book
+func (b *Book) Add(l BookLine) error {
+	pos := InitialPosition()
+
+	if err := pos.Replay(l.Moves); err != nil {
+		return err
+	}
+
+	pos = InitialPosition()
+
+	for i := range l.Moves {
+		pos.Replay(l.Moves[i : i+1])
+
+		key := pos.canonical()
+
+		if b.positions[key] == nil {
+			b.positions[key] = &bookEntry{}
+		}
+	}
+
+	e := b.positions[pos.canonical()]
+	e.sum += l.Score
+	e.n++
+
+	if l.Name != "" {
+		e.name = l.Name
+	}
+
+	b.lines = append(b.lines, l)
+
+	b.mu.Lock()
+	b.values = nil
+	b.mu.Unlock()
+
+	return nil
+}
+
+// AddGame adds the first depth moves of a recorded game to the Book
+// with the final disc differential for black as the score
+func (b *Book) AddGame(moves []Point, depth int) error {
+	pos := InitialPosition()
+
+	if err := pos.Replay(moves); err != nil {
+		return err
+	}
+
+	black, white, _ := pos.Count()
+
+	if len(moves) > depth {
+		moves = moves[:depth]
+	}
+
+	return b.Add(BookLine{Moves: moves, Score: black - white})
+}
+
+// entry returns the bookEntry of pos, or nil when pos is out of the Book
+func (b *Book) entry(pos *Position) *bookEntry {
+	return b.positions[pos.canonical()]
+}
+
+// Name returns the name of the opening at pos
+func (b *Book) Name(pos Position) (string, bool) {
+	e := b.entry(&pos)
+
+	if e == nil || e.name == "" {
+		return "", false
+	}
+
+	return e.name, true
+}
+
+// Opening returns the name of the last named position while moves stay in the Book
+func (b *Book) Opening(moves []Point) (name string) {
+	pos := InitialPosition()
+
+	for i := range moves {
+		if pos.Replay(moves[i:i+1]) != nil {
+			break
+		}
+
+		e := b.entry(&pos)
+
+		if e == nil {
+			break
+		}
+
+		if e.name != "" {
+			name = e.name
+		}
+	}
+
+	return
+}
+
+// value returns the evaluation of pos for black by minimax over the Book.
+// It must be called with b.mu held.
+func (b *Book) value(pos *Position) (v int, ok bool) {
+	key := pos.canonical()
+	e := b.positions[key]
+
+	if e == nil {
+		return 0, false
+	}
+
+	if bv, cached := b.values[key]; cached {
+		return bv.v, bv.ok
+	}
+
+	v, ok = b.minimax(pos, e)
+
+	if b.values == nil {
+		b.values = map[positionKey]bookValue{}
+	}
+
+	b.values[key] = bookValue{v: v, ok: ok}
+
+	return
+}
+
+// minimax returns the value of pos from the values of its children in the Book,
+// or the score of the lines ending at e when there are none
+func (b *Book) minimax(pos *Position, e *bookEntry) (v int, ok bool) {
+	found := false
+
+	for _, m := range pos.Moves() {
+		flips := pos.play(m)
+		cv, cok := b.value(pos)
+		pos.unplay(m, flips)
+
+		if !cok {
+			continue
+		}
+
+		if !found || (pos.crr == BLACK && cv > v) || (pos.crr == WHITE && cv < v) {
+			v, found = cv, true
+		}
+	}
+
+	if found {
+		return v, true
+	}
+
+	if e.n == 0 {
+		return 0, false
+	}
+
+	return e.sum / e.n, true
+}
+
+// Move returns the best move in the Book for the Player to move
+func (b *Book) Move(pos Position) (best Point, ok bool) {
+	var max int
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, m := range pos.Moves() {
+		flips := pos.play(m)
+		v, vok := b.value(&pos)
+		pos.unplay(m, flips)
+
+		if !vok {
+			continue
+		}
+
+		if pos.crr == WHITE {
+			v = -v
+		}
+
+		if !ok || v > max {
+			best, max, ok = m, v, true
+		}
+	}
+
+	return
+}
+
+// BookEngine is an Engine which plays book moves instantly
+// and leaves moves out of the Book to Engine
+type BookEngine struct {
+	Book   *Book
+	Engine Engine
+}
+
+// NewBookEngine returns a BookEngine
+func NewBookEngine(b *Book, e Engine) *BookEngine {
+	return &BookEngine{Book: b, Engine: e}
+}
+
+// Move returns the book move if any, or the move of Engine
+func (e *BookEngine) Move(pos Position) (Point, error) {
+	if p, ok := e.Book.Move(pos); ok {
+		return p, nil
+	}
+
+	return e.Engine.Move(pos)
+}
+
+// SetBook sets the Book to name the opening of the Game
+func (g *Game) SetBook(b *Book) {
+	g.book = b
+}
+
+// moves returns the points of the put history
+func (g *Game) moves() []Point {
+	moves := make([]Point, len(g.history))
+
+	for i, r := range g.history {
+		moves[i] = r.point
+	}
+
+	return moves
+}
+
+// printOpening prints the name of the opening when it changes
+func (g *Game) printOpening() {
+	// the Book knows only openings of 8 x 8 board by STANDARD rules from the normal beginning
+	if g.book == nil || g.b.size() != 8 || g.rules != STANDARD || !g.start.isInitial() {
+		return
+	}
+
+	name := g.book.Opening(g.moves())
+
+	if name != g.opening && name != "" {
+		fmt.Printf("OPENING: %s\n", name)
+	}
+
+	g.opening = name
+}
@@ -0,0 +1,172 @@
+package mrsoft
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBookOpening(t *testing.T) {
+	b := DefaultBook()
+
+	tests := map[string]string{
+		"f5d6":               "Perpendicular",
+		"f5d6c3d3c4":         "Tiger",
+		"f5d6c3d3c4f4":       "Tiger",
+		"f5f6e6f4c3":         "Buffalo",
+		"f5d6c5f4e3":         "Cow",
+		"f5d6c5f4e3f6g5e6e7": "Rose",
+		"d3c3":               "Diagonal",
+		"a1":                 "",
+	}
+
+	for s, want := range tests {
+		moves, err := ParseMoves(s)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := b.Opening(moves); got != want {
+			t.Errorf("Opening(%s) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestBookSymmetry(t *testing.T) {
+	b := DefaultBook()
+	tiger, _ := ParseMoves("f5d6c3d3c4")
+
+	for k := 0; k < 8; k++ {
+		moves := make([]Point, len(tiger))
+		for i, p := range tiger {
+			moves[i] = p.transform(k, 8)
+		}
+
+		pos := InitialPosition()
+		if pos.Replay(moves) != nil {
+			// the symmetry doesn't keep the initial position
+			continue
+		}
+
+		if got := b.Opening(moves); got != "Tiger" {
+			t.Errorf("Opening(%s) = %q, want Tiger", FormatMoves(moves), got)
+		}
+	}
+}
+
+func TestBookMove(t *testing.T) {
+	b := DefaultBook()
+	pos := InitialPosition()
+
+	p, ok := b.Move(pos)
+
+	if !ok {
+		t.Fatal("There is no book move at the initial position")
+	}
+
+	pos.play(p)
+
+	// white avoids the parallel opening
+	p, ok = b.Move(pos)
+
+	if !ok {
+		t.Fatal("There is no book move after the first move")
+	}
+
+	pos.play(p)
+
+	if name, _ := b.Name(pos); name == "Parallel" {
+		t.Fatal("White played the parallel opening")
+	}
+
+	e := NewBookEngine(b, NewMCTS())
+
+	if _, err := e.Move(InitialPosition()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBookValueCache(t *testing.T) {
+	b, err := ReadBook(strings.NewReader("f5d6 0\nf5f6 1\nf5f6e6f4 2\nf5f6e6d6 2\n"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pos := InitialPosition()
+	pos.Replay([]Point{{6, 5}})
+
+	if p, _ := b.Move(pos); !p.equal(Point{4, 6}) {
+		t.Fatalf("book move is %s, want d6", p.Notation())
+	}
+
+	// f4 and d6 after f5 f6 e6 are the same position under symmetry
+	if len(b.values) == 0 || len(b.values) > len(b.positions) {
+		t.Fatalf("%d values are cached for %d positions", len(b.values), len(b.positions))
+	}
+
+	// the cached values are dropped by a new line
+	if err = b.Add(BookLine{Moves: []Point{{6, 5}, {6, 6}, {5, 6}, {6, 4}, {7, 5}}, Score: -6}); err != nil {
+		t.Fatal(err)
+	}
+
+	if p, _ := b.Move(pos); !p.equal(Point{6, 6}) {
+		t.Fatalf("book move is %s after adding a line, want f6", p.Notation())
+	}
+}
+
+func TestBookBuild(t *testing.T) {
+	b := NewBook()
+
+	moves, _ := ParseMoves("f5d6c3d3c4f4f6f3e6e7")
+	game := randomGame(moves)
+
+	if err := b.AddGame(game, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err := b.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadBook(buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pos := InitialPosition()
+	pos.Replay(game[:5])
+
+	p, ok := read.Move(pos)
+
+	if !ok || !p.equal(game[5]) {
+		t.Fatalf("book move is %v, want %v", p, game[5])
+	}
+}
+
+// randomGame plays random moves after prefix until the end of the game
+func randomGame(prefix []Point) []Point {
+	pos := InitialPosition()
+	pos.Replay(prefix)
+
+	moves := append([]Point{}, prefix...)
+
+	for i := 0; !pos.IsFinish(); i++ {
+		available := pos.Moves()
+
+		if len(available) == 0 {
+			pos.pass()
+			continue
+		}
+
+		m := available[i%len(available)]
+		pos.play(m)
+		moves = append(moves, m)
+	}
+
+	return moves
+}
@@ -0,0 +1,190 @@
+package mrsoft
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+)
+
+// TimeControl is the time given to each Player
+type TimeControl struct {
+	// Main is the time for the whole Game
+	Main time.Duration
+	// Increment is added to the main time after every move
+	Increment time.Duration
+	// Byoyomi is the time for each move after the main time runs out
+	Byoyomi time.Duration
+}
+
+// MoveTime is the clock of a move
+type MoveTime struct {
+	// Used is the time spent on the move
+	Used time.Duration `json:"used"`
+	// Left is the main time left after the move
+	Left time.Duration `json:"left"`
+}
+
+// Clock measures the time of both Players.
+// Only the time waiting for a move counts and coil flips are not charged.
+type Clock struct {
+	tc   TimeControl
+	left map[Player]time.Duration
+	// Player whose time is counted, NONE while paused
+	running Player
+	// when the running Player started and the time used in this turn before it
+	since time.Time
+	used  time.Duration
+}
+
+// NewClock returns a Clock which gives the main time to both Players
+func NewClock(tc TimeControl) *Clock {
+	return &Clock{
+		tc:   tc,
+		left: map[Player]time.Duration{BLACK: tc.Main, WHITE: tc.Main},
+	}
+}
+
+// Timed plays the Game with the TimeControl
+func Timed(tc TimeControl) Option {
+	return func(g *Game) error {
+		if tc.Main <= 0 && tc.Byoyomi <= 0 {
+			return errors.New("Time control must have main time or byoyomi")
+		}
+
+		g.clock = NewClock(tc)
+		return nil
+	}
+}
+
+// Clock returns the clock of the Game, nil if not timed
+func (g *Game) Clock() *Clock {
+	return g.clock
+}
+
+// Left returns the main time left for pl
+func (c *Clock) Left(pl Player) time.Duration {
+	return c.left[pl]
+}
+
+// Used returns the time spent by pl in the current turn
+func (c *Clock) Used(pl Player) time.Duration {
+	if c.running != pl {
+		return c.used
+	}
+
+	return c.used + time.Since(c.since)
+}
+
+// remaining returns how long pl can think in the current turn
+func (c *Clock) remaining(pl Player) time.Duration {
+	return c.left[pl] + c.tc.Byoyomi - c.Used(pl)
+}
+
+// start counts the time of pl
+func (c *Clock) start(pl Player) {
+	if c == nil || c.running == pl {
+		return
+	}
+
+	c.running, c.since = pl, time.Now()
+}
+
+// pause stops counting the time until start
+func (c *Clock) pause() {
+	if c == nil || c.running == NONE {
+		return
+	}
+
+	c.used += time.Since(c.since)
+	c.running = NONE
+}
+
+// flagged reports whether pl has run out of time
+func (c *Clock) flagged(pl Player) bool {
+	return c != nil && c.remaining(pl) < 0
+}
+
+// finish charges the time used in this turn to pl and starts a new turn.
+// before is the main time left before the move.
+func (c *Clock) finish(pl Player) (mt MoveTime, before time.Duration) {
+	if c == nil {
+		return
+	}
+
+	c.pause()
+
+	before = c.left[pl]
+
+	mt.Used = c.used
+	c.used = 0
+
+	if mt.Used <= c.left[pl] {
+		c.left[pl] += c.tc.Increment - mt.Used
+	} else {
+		// main time runs out and the move is in byoyomi
+		c.left[pl] = 0
+	}
+
+	mt.Left = c.left[pl]
+
+	return
+}
+
+// restore gives back the time before the move of pl to undo it
+func (c *Clock) restore(pl Player, left time.Duration) {
+	if c == nil {
+		return
+	}
+
+	c.pause()
+
+	c.used = 0
+	c.left[pl] = left
+}
+
+// timedInput is implemented by middlewares which can give up waiting for input
+type timedInput interface {
+	GetInputTimeout(time.Duration) (int, int, error)
+}
+
+// minWait is the shortest wait for input, since the middleware waits forever for 0
+const minWait = time.Millisecond
+
+// readInput waits for input of the current Player until the time runs out.
+// Point{} is returned at once when the time has already run out.
+func (g *Game) readInput() (p Point, err error) {
+	var x, y int
+
+	if g.clock.flagged(g.crr) {
+		return Point{}, nil
+	}
+
+	if t, ok := g.m.(timedInput); ok && g.clock != nil {
+		d := g.clock.remaining(g.crr)
+
+		if d < minWait {
+			d = minWait
+		}
+
+		x, y, err = t.GetInputTimeout(d)
+
+		if err == mrmiddle.ErrTimeout {
+			return Point{}, nil
+		}
+	} else {
+		x, y, err = g.m.GetInput()
+	}
+
+	return Point{x, y}, err
+}
+
+// printClock prints the time left of both Players
+func (g *Game) printClock() {
+	if g.clock == nil {
+		return
+	}
+
+	fmt.Printf("CLOCK\tBLACK %s\tWHITE %s\n", g.clock.Left(BLACK), g.clock.Left(WHITE))
+}
@@ -0,0 +1,137 @@
+package mrsoft
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+)
+
+// timedMiddleware waits for input until the timeout
+type timedMiddleware struct {
+	dammyMiddleware
+}
+
+func (m *timedMiddleware) GetInputTimeout(d time.Duration) (int, int, error) {
+	if len(m.r) <= m.t {
+		time.Sleep(d)
+		return 0, 0, mrmiddle.ErrTimeout
+	}
+
+	return m.GetInput()
+}
+
+// liftingLateMiddleware lifts a stone after the time runs out and fails if it is asked to wait forever
+type liftingLateMiddleware struct {
+	dammyMiddleware
+	waits []time.Duration
+}
+
+func (m *liftingLateMiddleware) GetInputTimeout(d time.Duration) (int, int, error) {
+	m.waits = append(m.waits, d)
+
+	if d <= 0 {
+		return 0, 0, errors.New("Waits forever")
+	}
+
+	time.Sleep(d + 10*time.Millisecond)
+
+	return int(mrmiddle.REMOVE), int(mrmiddle.REMOVE), nil
+}
+
+func TestTimeUsedUp(t *testing.T) {
+	m := &liftingLateMiddleware{}
+
+	g, err := NewGame(m, Timed(TimeControl{Main: 20 * time.Millisecond}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// no input is waited for after the time is used up
+	if len(m.waits) != 1 || g.Record().TimeLoss != BLACK {
+		t.Fatalf("waited for input %v and %s lost on time, want BLACK lost", m.waits, g.Record().TimeLoss)
+	}
+}
+
+func TestIllegalMoveTime(t *testing.T) {
+	// the illegal stone on a1 is left on the board
+	m := &timedMiddleware{dammyMiddleware{r: [][2]int{{1, 1}}}}
+
+	g, err := NewGame(m, Timed(TimeControl{Main: 30 * time.Millisecond}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := g.Record(); r.TimeLoss != BLACK || g.Illegals(BLACK) != 1 {
+		t.Fatalf("%s lost on time after %d illegal moves, want BLACK lost", r.TimeLoss, g.Illegals(BLACK))
+	}
+}
+
+func TestClockFinish(t *testing.T) {
+	c := NewClock(TimeControl{Main: 10 * time.Second, Increment: time.Second, Byoyomi: 5 * time.Second})
+
+	c.used = 4 * time.Second
+	if mt, before := c.finish(BLACK); mt.Used != 4*time.Second || mt.Left != 7*time.Second || before != 10*time.Second {
+		t.Fatalf("finish returns %v and %s, want 7s left after 4s from 10s", mt, before)
+	}
+
+	// the main time runs out and the move is in byoyomi
+	c.used = 9 * time.Second
+	if mt, _ := c.finish(BLACK); mt.Left != 0 {
+		t.Fatalf("%s is left after byoyomi", mt.Left)
+	}
+
+	c.used = 4 * time.Second
+	if c.flagged(BLACK) {
+		t.Fatal("BLACK is flagged within byoyomi")
+	}
+
+	c.used = 6 * time.Second
+	if !c.flagged(BLACK) {
+		t.Fatal("BLACK is not flagged after byoyomi")
+	}
+
+	c.restore(BLACK, 10*time.Second)
+	if c.Left(BLACK) != 10*time.Second || c.Used(BLACK) != 0 {
+		t.Fatalf("restore leaves %s and %s used", c.Left(BLACK), c.Used(BLACK))
+	}
+}
+
+func TestTimeLoss(t *testing.T) {
+	m := &timedMiddleware{dammyMiddleware{r: [][2]int{{3, 4}}}}
+
+	g, err := NewGame(m, Timed(TimeControl{Main: 50 * time.Millisecond, Byoyomi: 50 * time.Millisecond}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := g.Record()
+
+	if r.TimeLoss != WHITE || r.Winner() != BLACK {
+		t.Fatalf("%s lost on time and %s wins, want WHITE lost", r.TimeLoss, r.Winner())
+	}
+
+	if len(r.Times) != 1 || r.Times[0].Left > 50*time.Millisecond {
+		t.Fatalf("Record has clocks %v", r.Times)
+	}
+
+	if _, err = NewGame(m, Timed(TimeControl{})); err == nil {
+		t.Fatal("NewGame accepts time control without time")
+	}
+}
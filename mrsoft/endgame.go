@@ -0,0 +1,328 @@
+package mrsoft
+
+import (
+	"errors"
+	"sort"
+)
+
+// minimum number of blank cells to use fastest-first ordering and the table
+const (
+	fastestFirstEmpties = 7
+	tableEmpties        = 7
+)
+
+// ScoreUnit is the unit of the scores given by an Analyzer
+type ScoreUnit int
+
+const (
+	// POINTS are the heuristic evaluation of the Searcher
+	POINTS ScoreUnit = iota
+	// DISCS are the final disc differential read out by the Solver
+	DISCS
+	// PERCENT is the win rate of the playouts of MCTS
+	PERCENT
+)
+
+func (u ScoreUnit) String() string {
+	switch u {
+	case POINTS:
+		return "points"
+	case DISCS:
+		return "discs"
+	case PERCENT:
+		return "percent"
+	default:
+		return "unknown"
+	}
+}
+
+// MoveScore represents an evaluation of a move from the view of the Player to move
+type MoveScore struct {
+	Move  Point
+	Score int
+	Unit  ScoreUnit
+}
+
+// Solver is an Engine which reads the game out to the end
+// and knows the exact final disc differential.
+// Solver is not safe for concurrent use.
+type Solver struct {
+	// Empties is the number of blank cells from which Endgame uses the Solver
+	Empties int
+	// TableSize is the number of entries of the transposition table made when Table is nil
+	TableSize int
+	// Table is the transposition table, which may be shared with other Solvers
+	Table *TranspositionTable
+	// Nodes is the number of searched positions
+	Nodes int
+
+	// blank cells of the searched position
+	empties []Point
+}
+
+// NewSolver returns a Solver with default settings
+func NewSolver() *Solver {
+	return &Solver{
+		Empties:   14,
+		TableSize: 1 << 18,
+	}
+}
+
+// Solve returns the exact final disc differential for the Player to move,
+// which is negated by ANTI rules
+func (s *Solver) Solve(pos Position) int {
+	s.prepare(&pos)
+	bound := pos.maxScore()
+
+	return s.search(&pos, -bound, bound, false)
+}
+
+// Analyze returns the exact final disc differential of each available move
+// from the view of the Player to move, in descending order of score
+func (s *Solver) Analyze(pos Position) ([]MoveScore, error) {
+	moves := pos.Moves()
+
+	if len(moves) == 0 {
+		return nil, errors.New("There is no available point")
+	}
+
+	s.prepare(&pos)
+	bound := pos.maxScore()
+
+	scores := make([]MoveScore, len(moves))
+
+	for i, m := range moves {
+		flips := pos.play(m)
+		scores[i] = MoveScore{Move: m, Score: -s.search(&pos, -bound, bound, false), Unit: DISCS}
+		pos.unplay(m, flips)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	return scores, nil
+}
+
+// Move returns the move which gives the best final disc differential
+func (s *Solver) Move(pos Position) (Point, error) {
+	moves := pos.Moves()
+
+	if len(moves) == 0 {
+		return Point{}, errors.New("There is no available point")
+	}
+
+	s.prepare(&pos)
+
+	bound := pos.maxScore()
+	best, alpha := moves[0], -bound
+
+	for _, m := range s.order(&pos, moves) {
+		flips := pos.play(m)
+		v := -s.search(&pos, -bound, -alpha, false)
+		pos.unplay(m, flips)
+
+		if v > alpha {
+			best, alpha = m, v
+		}
+	}
+
+	return best, nil
+}
+
+// prepare initializes the Solver for searching from pos
+func (s *Solver) prepare(pos *Position) {
+	if s.Table == nil {
+		s.Table = NewTranspositionTable(s.TableSize)
+	}
+
+	s.Table.NewSearch()
+
+	s.Nodes = 0
+	s.empties = s.empties[:0]
+	n := pos.b.size()
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			if pos.b[y][x] == NONE {
+				s.empties = append(s.empties, Point{x, y})
+			}
+		}
+	}
+}
+
+// search is a negamax search with alpha-beta pruning.
+// passed reports whether the last move was a pass.
+func (s *Solver) search(pos *Position, alpha, beta int, passed bool) int {
+	s.Nodes++
+
+	moves := s.moves(pos, pos.crr)
+
+	if len(moves) == 0 {
+		if passed {
+			return pos.score(pos.crr)
+		}
+
+		pos.pass()
+		v := -s.search(pos, -beta, -alpha, true)
+		pos.pass()
+
+		return v
+	}
+
+	var hint *Point
+	empties := s.countEmpties(pos)
+	useTable := empties >= tableEmpties
+
+	if useTable {
+		if e, ok := s.Table.Probe(pos.Zobrist()); ok {
+			if e.cutoff(alpha, beta) {
+				return e.Score
+			}
+
+			hint = &e.Best
+		}
+	}
+
+	moves = s.order(pos, moves)
+
+	// try the best move of the last search first
+	for i, m := range moves {
+		if hint != nil && m.equal(*hint) {
+			copy(moves[1:i+1], moves[:i])
+			moves[0] = m
+			break
+		}
+	}
+
+	a, best, bestMove := alpha, -pos.maxScore(), moves[0]
+	flips := make([]Point, 0, 20)
+
+	for _, m := range moves {
+		flips = pos.playAppend(m, flips[:0])
+		v := -s.search(pos, -beta, -a, false)
+		pos.unplay(m, flips)
+
+		if v > best {
+			best, bestMove = v, m
+		}
+
+		if v > a {
+			a = v
+		}
+
+		if a >= beta {
+			break
+		}
+	}
+
+	if useTable {
+		s.Table.Store(TTEntry{Key: pos.Zobrist(), Score: best, Depth: empties, Bound: boundOf(best, alpha, beta), Best: bestMove})
+	}
+
+	return best
+}
+
+// moves returns the available points of pl among blank cells
+func (s *Solver) moves(pos *Position, pl Player) []Point {
+	moves := []Point{}
+
+	for _, p := range s.empties {
+		if pos.b.canPut(p, pl) {
+			moves = append(moves, p)
+		}
+	}
+
+	return moves
+}
+
+// countEmpties returns the number of blank cells of pos
+func (s *Solver) countEmpties(pos *Position) (n int) {
+	for _, p := range s.empties {
+		if pos.At(p) == NONE {
+			n++
+		}
+	}
+
+	return
+}
+
+// quadrant returns the index of the quadrant of n x n board which p belongs to
+func quadrant(p Point, n int) int {
+	return (p[0]-1)*2/n + 2*((p[1]-1)*2/n)
+}
+
+// order sorts moves so that the search cuts off early.
+// Moves into quadrants with odd number of blank cells (parity) come first,
+// and moves which leave the enemy less mobility come first (fastest-first).
+func (s *Solver) order(pos *Position, moves []Point) []Point {
+	if len(moves) < 2 {
+		return moves
+	}
+
+	parity := [4]int{}
+	size := pos.b.size()
+	n := 0
+
+	for _, p := range s.empties {
+		if pos.At(p) == NONE {
+			parity[quadrant(p, size)]++
+			n++
+		}
+	}
+
+	keys := make([]int, len(moves))
+
+	for i, m := range moves {
+		if parity[quadrant(m, size)]%2 == 0 {
+			keys[i] = 1
+		}
+
+		if n >= fastestFirstEmpties {
+			flips := pos.play(m)
+			keys[i] += 2 * len(s.moves(pos, pos.crr))
+			pos.unplay(m, flips)
+		}
+
+		if isCorner(m, size) {
+			keys[i]--
+		}
+	}
+
+	sort.Stable(byKeys{moves, keys})
+
+	return moves
+}
+
+type byKeys struct {
+	moves []Point
+	keys  []int
+}
+
+func (b byKeys) Len() int           { return len(b.moves) }
+func (b byKeys) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKeys) Swap(i, j int) {
+	b.moves[i], b.moves[j] = b.moves[j], b.moves[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+}
+
+// Endgame is an Engine which solves the game exactly when the number of
+// blank cells is not more than Solver.Empties, and leaves earlier moves to Engine
+type Endgame struct {
+	Engine Engine
+	Solver *Solver
+}
+
+// NewEndgame returns an Endgame which falls back to the Solver on e
+func NewEndgame(e Engine) *Endgame {
+	return &Endgame{Engine: e, Solver: NewSolver()}
+}
+
+// Move returns the perfect move in the endgame, or the move of Engine
+func (e *Endgame) Move(pos Position) (Point, error) {
+	if _, _, blank := pos.Count(); blank <= e.Solver.Empties {
+		return e.Solver.Move(pos)
+	}
+
+	return e.Engine.Move(pos)
+}
@@ -0,0 +1,179 @@
+package mrsoft
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomPosition plays random moves from the initial position until blank cells become empties
+func randomPosition(seed int64, empties int) Position {
+	r := rand.New(rand.NewSource(seed))
+	pos := InitialPosition()
+
+	for {
+		if _, _, blank := pos.Count(); blank <= empties || pos.IsFinish() {
+			return pos
+		}
+
+		moves := pos.Moves()
+
+		if len(moves) == 0 {
+			pos.pass()
+			continue
+		}
+
+		pos.play(moves[r.Intn(len(moves))])
+	}
+}
+
+// minimax reads out all moves without pruning
+func minimax(pos Position, passed bool) int {
+	moves := pos.Moves()
+
+	if len(moves) == 0 {
+		if passed {
+			return pos.score(pos.crr)
+		}
+
+		pos.pass()
+		return -minimax(pos, true)
+	}
+
+	best := -pos.maxScore()
+	for _, m := range moves {
+		next := pos
+		next.play(m)
+
+		if v := -minimax(next, false); v > best {
+			best = v
+		}
+	}
+
+	return best
+}
+
+func TestSolverSolve(t *testing.T) {
+	s := NewSolver()
+
+	for seed := int64(1); seed <= 10; seed++ {
+		pos := randomPosition(seed, 9)
+
+		if got, want := s.Solve(pos), minimax(pos, false); got != want {
+			t.Fatalf("seed %d: Solve returned %d, want %d", seed, got, want)
+		}
+	}
+}
+
+func TestSolverAnalyze(t *testing.T) {
+	s := NewSolver()
+	pos := randomPosition(3, 10)
+
+	scores, err := s.Analyze(pos)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(scores) != len(pos.Moves()) {
+		t.Fatalf("Analyze returned %d moves, want %d", len(scores), len(pos.Moves()))
+	}
+
+	for _, ms := range scores {
+		next := pos
+		next.play(ms.Move)
+
+		if want := -minimax(next, false); ms.Score != want {
+			t.Fatalf("score of %v is %d, want %d", ms.Move, ms.Score, want)
+		}
+	}
+
+	best, err := s.Move(pos)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	next := pos
+	next.play(best)
+
+	if v := -minimax(next, false); v != scores[0].Score {
+		t.Fatalf("Move chose %v scoring %d, but the best score is %d", best, v, scores[0].Score)
+	}
+}
+
+func TestSolverLargeBoard(t *testing.T) {
+	s := NewSolver()
+
+	// black has almost all discs, so the scores exceed 64
+	board := strings.Repeat("XXXXXXXXXX", 9) + "-OOX-OOX--"
+
+	for _, turn := range []string{"X", "O"} {
+		pos, err := ParseBoard(board + turn)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := minimax(pos, false)
+
+		if want < 65 && want > -65 {
+			t.Fatalf("%s to move scores %d, which doesn't exceed 64", pos.Turn(), want)
+		}
+
+		if got := s.Solve(pos); got != want {
+			t.Fatalf("%s to move: Solve returned %d, want %d", pos.Turn(), got, want)
+		}
+
+		scores, err := s.Analyze(pos)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, ms := range scores {
+			next := pos
+			next.play(ms.Move)
+
+			if v := -minimax(next, false); ms.Score != v {
+				t.Fatalf("%s to move: score of %v is %d, want %d", pos.Turn(), ms.Move, ms.Score, v)
+			}
+		}
+
+		best, err := s.Move(pos)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		next := pos
+		next.play(best)
+
+		if v := -minimax(next, false); v != want {
+			t.Fatalf("%s to move: Move chose %v scoring %d, want %d", pos.Turn(), best, v, want)
+		}
+	}
+}
+
+func TestEndgameFallback(t *testing.T) {
+	m := &placingMiddleware{}
+	g, err := NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []Player{BLACK, WHITE} {
+		mcts := NewMCTS()
+		mcts.Playouts = 20
+		mcts.Seed = 1
+
+		e := NewEndgame(mcts)
+		e.Solver.Empties = 10
+		g.SetEngine(p, e)
+	}
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+}
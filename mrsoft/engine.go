@@ -0,0 +1,84 @@
+package mrsoft
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/sirupsen/logrus"
+)
+
+// Engine represents a computer player which chooses a move for the Player to move
+type Engine interface {
+	Move(pos Position) (Point, error)
+}
+
+// placer is implemented by middlewares which can put a stone by themselves.
+// On other middlewares the move of an Engine is put by hand.
+type placer interface {
+	Place(int, int, mrmiddle.Pole) error
+}
+
+// SetEngine lets the Engine play as the Player. nil makes the Player human.
+func (g *Game) SetEngine(p Player, e Engine) {
+	if e == nil {
+		delete(g.engines, p)
+		return
+	}
+
+	g.engines[p] = e
+}
+
+// hasHuman reports whether any Player is not played by an Engine
+func (g *Game) hasHuman() bool {
+	return g.engines[BLACK] == nil || g.engines[WHITE] == nil
+}
+
+// think asks the Engine for a move and waits until the stone is put on the board.
+// UNDO input is returned as it is.
+func (g *Game) think(e Engine) (p Point, err error) {
+	begin := time.Now()
+	p, err = e.Move(g.Position())
+	used := time.Since(begin)
+	thinkTime.WithLabelValues(g.crr.label()).Observe(used.Seconds())
+
+	if err != nil {
+		g.log.WithField("player", g.crr.label()).WithError(err).Error("Engine failed to move")
+		return Point{}, fmt.Errorf("Engine failed to move: %s", err)
+	}
+
+	g.cellLog(p).WithFields(logrus.Fields{"player": g.crr.label(), "think": used.Seconds()}).Debug("Engine move")
+
+	if len(g.available[p]) == 0 {
+		return Point{}, fmt.Errorf("Engine chose unavailable point (%d, %d)", p[0], p[1])
+	}
+
+	fmt.Printf("COMPUTER (%s) PUTS (%d, %d)\n", g.crr, p[0], p[1])
+
+	if pl, ok := g.m.(placer); ok {
+		// coils are not charged to the Player
+		g.clock.pause()
+		err = pl.Place(p[0], p[1], g.crr.color().pole())
+		return
+	}
+
+	for {
+		q, err := g.getInput()
+
+		if err != nil || g.clock.flagged(g.crr) {
+			return q, err
+		}
+
+		if q.equal(p) || q.equal(undoPoint) || q.equal(pausePoint) || q.equal(newPoint) {
+			return q, nil
+		}
+
+		if g.b.contains(q) {
+			if err = g.reject(q, "the computer does not put there"); err != nil {
+				return Point{}, err
+			}
+		}
+
+		fmt.Printf("Please put the stone on (%d, %d)\n", p[0], p[1])
+	}
+}
@@ -0,0 +1,137 @@
+package mrsoft
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventKind is the kind of an Event
+type EventKind int
+
+const (
+	// StartEvent is sent when the Game starts or is replaced by a new one
+	StartEvent EventKind = iota
+	// MoveEvent is a stone of Player put on Point, which flips Flips
+	MoveEvent
+	// PassEvent is a pass of Player
+	PassEvent
+	// UndoEvent is the move of Player on Point taken back
+	UndoEvent
+	// FinishEvent is the end of the Game won by Winner, NONE on draw
+	FinishEvent
+	// FaultEvent is a failure of the coils to flip the stone on Point
+	FaultEvent
+	// PauseEvent is sent when the Game is paused
+	PauseEvent
+	// ResumeEvent is sent when the paused Game continues
+	ResumeEvent
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case StartEvent:
+		return "start"
+	case MoveEvent:
+		return "move"
+	case PassEvent:
+		return "pass"
+	case UndoEvent:
+		return "undo"
+	case FinishEvent:
+		return "finish"
+	case FaultEvent:
+		return "fault"
+	case PauseEvent:
+		return "pause"
+	case ResumeEvent:
+		return "resume"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is something which happened in the Game
+type Event struct {
+	Kind   EventKind
+	Player Player
+	Point  Point
+	Flips  []Point
+	Winner Player
+	Err    error
+	// Position is the position after the Event
+	Position Position
+	// Ply is the number of moves and passes played
+	Ply  int
+	Time time.Time
+}
+
+// Observer is told the Events of the Game as they happen.
+// Observe is called by the goroutine playing the Game.
+type Observer interface {
+	Observe(e Event)
+}
+
+// SetObserver sets the Observer of the Game. nil disables it.
+func (g *Game) SetObserver(o Observer) {
+	g.observer = o
+}
+
+// notify tells the Event to the Observer, and to the Rater if it is an Observer
+func (g *Game) notify(e Event) {
+	ro, rated := g.rater.(Observer)
+
+	if g.observer == nil && !rated {
+		return
+	}
+
+	e.Position = g.Position()
+	e.Ply = len(g.history)
+
+	// put notifies before the turn passes to the opponent
+	if e.Kind == MoveEvent {
+		e.Position.crr = e.Player.enemy()
+	}
+
+	e.Time = time.Now()
+
+	if rated {
+		ro.Observe(e)
+	}
+
+	if g.observer != nil {
+		g.observer.Observe(e)
+	}
+}
+
+// pause stops the Game until RESUME or NEWGAME is sent and returns it.
+// The clock is stopped meanwhile and the stones are checked by the sensors when the Game continues.
+// Other commands are rejected and stones put meanwhile are ignored.
+func (g *Game) pause() (Point, error) {
+	fmt.Println("PAUSED")
+	g.notify(Event{Kind: PauseEvent, Player: g.crr})
+
+	for {
+		x, y, err := g.m.GetInput()
+
+		if err != nil {
+			return Point{}, err
+		}
+
+		p := Point{x, y}
+
+		switch {
+		case p.equal(newPoint):
+			return p, nil
+		case p.equal(resumePoint):
+			fmt.Println("RESUMED")
+			g.notify(Event{Kind: ResumeEvent, Player: g.crr})
+
+			pos := g.Position()
+
+			return p, g.verify(&pos)
+		case p[0] < 0 && !p.equal(removePoint):
+			fmt.Println("PAUSED: RESUME OR START A NEW GAME FIRST")
+			g.log.WithField("command", p[0]).Warn("Command is rejected while paused")
+		}
+	}
+}
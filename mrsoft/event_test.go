@@ -0,0 +1,96 @@
+package mrsoft
+
+import (
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+)
+
+// eventRecorder records the Events of the Game
+type eventRecorder struct {
+	events []Event
+}
+
+func (r *eventRecorder) Observe(e Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *eventRecorder) kinds() (kinds []string) {
+	for _, e := range r.events {
+		kinds = append(kinds, e.Kind.String())
+	}
+
+	return
+}
+
+func TestEvents(t *testing.T) {
+	pause, resume, newGame, undo := int(mrmiddle.PAUSE), int(mrmiddle.RESUME), int(mrmiddle.NEWGAME), int(mrmiddle.UNDO)
+
+	m := &dammyMiddleware{
+		r: [][2]int{
+			{1, 2},
+			{pause, pause},
+			// stones put while paused are ignored
+			{1, 3},
+			{resume, resume},
+			{1, 3},
+			{pause, pause},
+			// undo is rejected while paused
+			{undo, undo},
+			{newGame, newGame},
+			{3, 4},
+		},
+	}
+
+	g, err := NewGame(m, Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &eventRecorder{}
+	g.SetObserver(r)
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	want := []string{"start", "move", "pause", "resume", "move", "pause", "start", "move"}
+
+	if got := r.kinds(); len(got) != len(want) {
+		t.Fatalf("events are %v, want %v", got, want)
+	}
+
+	for i, k := range r.kinds() {
+		if k != want[i] {
+			t.Fatalf("events are %v, want %v", r.kinds(), want)
+		}
+	}
+
+	if e := r.events[4]; e.Player != WHITE || e.Point != (Point{1, 3}) || len(e.Flips) != 1 || e.Ply != 2 || e.Position.Turn() != BLACK {
+		t.Fatalf("second move is %+v", e)
+	}
+
+	if s := FormatMoves(g.Record().Moves); s != "c4" {
+		t.Fatalf("moves of the new game are %s, want c4", s)
+	}
+}
+
+func TestPassEvent(t *testing.T) {
+	g, err := NewGame(&dammyMiddleware{r: passMoves}, Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &eventRecorder{}
+	g.SetObserver(r)
+
+	g.Start()
+
+	last := r.events[len(r.events)-1]
+
+	if last.Kind != PassEvent || last.Player != BLACK || last.Position.Turn() != WHITE {
+		t.Fatalf("last event is %s of %s, want pass of BLACK", last.Kind, last.Player)
+	}
+}
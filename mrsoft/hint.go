@@ -0,0 +1,61 @@
+package mrsoft
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Analyzer evaluates every available move of a Position
+type Analyzer interface {
+	Analyze(pos Position) ([]MoveScore, error)
+}
+
+// Analyze returns the exact scores of the Solver in the endgame,
+// or the scores of Engine if it is an Analyzer
+func (e *Endgame) Analyze(pos Position) ([]MoveScore, error) {
+	if _, _, blank := pos.Count(); blank <= e.Solver.Empties {
+		return e.Solver.Analyze(pos)
+	}
+
+	a, ok := e.Engine.(Analyzer)
+
+	if !ok {
+		return nil, errors.New("Engine can't analyze moves")
+	}
+
+	return a.Analyze(pos)
+}
+
+// SetAnalyzer sets the Analyzer for hints
+func (g *Game) SetAnalyzer(a Analyzer) {
+	g.analyzer = a
+}
+
+// Hint returns the evaluation of every available point of the current Player,
+// the recommended move first. The Game is not changed.
+func (g *Game) Hint() ([]MoveScore, error) {
+	g.setAvailable()
+
+	if len(g.available) == 0 {
+		return nil, errors.New("There is no available point")
+	}
+
+	return g.analyzer.Analyze(g.Position())
+}
+
+func (g *Game) printHint() error {
+	scores, err := g.Hint()
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("# HINT FOR %s\n", g.crr)
+
+	for i, s := range scores {
+		fmt.Printf("[%2d]\t(%d, %d)\t%s\tSCORE: %d %s\n", i+1, s.Move[0], s.Move[1], s.Move.Notation(), s.Score, strings.ToUpper(s.Unit.String()))
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package mrsoft
+
+import "testing"
+
+func TestHint(t *testing.T) {
+	m := &dammyMiddleware{
+		r: [][2]int{
+			[2]int{3, 4},
+			[2]int(hintPoint),
+			[2]int{3, 3},
+		},
+	}
+
+	g, err := NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Start fails at the end of the input
+	g.Start()
+
+	if len(g.history) != 2 {
+		t.Fatalf("history has %d records, want 2", len(g.history))
+	}
+
+	g.setAvailable()
+	b := g.b
+
+	scores, err := g.Hint()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(scores) != len(g.available) {
+		t.Fatalf("Hint returned %d moves, want %d", len(scores), len(g.available))
+	}
+
+	for i, s := range scores {
+		if len(g.available[s.Move]) == 0 {
+			t.Fatalf("Hint returned unavailable point %v", s.Move)
+		}
+
+		if i > 0 && scores[i-1].Score < s.Score {
+			t.Fatal("Hint is not sorted by score")
+		}
+
+		if s.Unit != POINTS {
+			t.Fatalf("Hint in the opening is in %s, want points", s.Unit)
+		}
+	}
+
+	if g.b != b || len(g.history) != 2 || g.crr != BLACK {
+		t.Fatal("Hint changed the Game")
+	}
+}
+
+func TestSearcherTakesCorner(t *testing.T) {
+	// black can take the corner (1, 1) or play (8, 2)
+	pos := InitialPosition()
+	pos.b[4][4], pos.b[4][5], pos.b[5][4], pos.b[5][5] = NONE, NONE, NONE, NONE
+	pos.b[2][2], pos.b[3][3] = WHITE, BLACK
+	pos.b[2][7], pos.b[2][6] = WHITE, BLACK
+
+	s := NewSearcher()
+
+	p, err := s.Move(pos)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !p.equal(Point{1, 1}) {
+		t.Fatalf("Searcher chose %v, want (1, 1)", p)
+	}
+}
+
+func TestScoreUnits(t *testing.T) {
+	pos, _ := NewPosition(4)
+
+	mcts := NewMCTS()
+	mcts.Playouts = 50
+	mcts.Seed = 1
+
+	for _, c := range []struct {
+		a    Analyzer
+		unit ScoreUnit
+	}{
+		{NewSearcher(), POINTS},
+		{NewSolver(), DISCS},
+		{mcts, PERCENT},
+		// 4 x 4 board is solved from the start
+		{NewEndgame(NewSearcher()), DISCS},
+	} {
+		scores, err := c.a.Analyze(pos)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, s := range scores {
+			if s.Unit != c.unit {
+				t.Fatalf("%T returns %s, want %s", c.a, s.Unit, c.unit)
+			}
+		}
+	}
+}
+
+func TestScoreDiscs(t *testing.T) {
+	for _, c := range []struct {
+		s     MoveScore
+		discs float64
+		ok    bool
+	}{
+		{MoveScore{Score: 12, Unit: DISCS}, 12, true},
+		{MoveScore{Score: -25, Unit: POINTS}, -2.5, true},
+		// a won or lost game is found by the Searcher
+		{MoveScore{Score: winScore + 6, Unit: POINTS}, 6, true},
+		{MoveScore{Score: -winScore - 4, Unit: POINTS}, -4, true},
+		{MoveScore{Score: 60, Unit: PERCENT}, 0, false},
+	} {
+		if discs, ok := c.s.Discs(); discs != c.discs || ok != c.ok {
+			t.Fatalf("%+v is %v discs, %v, want %v discs, %v", c.s, discs, ok, c.discs, c.ok)
+		}
+	}
+}
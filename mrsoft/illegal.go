@@ -0,0 +1,78 @@
+package mrsoft
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// illegalReason returns why the current Player can't put a stone on p, or "" if p is available
+func (g *Game) illegalReason(p Point) string {
+	switch {
+	case !g.b.contains(p):
+		return "out of the board"
+	case g.b[p[1]][p[0]] != NONE:
+		return "there is already a stone"
+	case len(g.available[p]) != 0:
+		return ""
+	case g.b.canPut(p, g.crr.enemy()):
+		return fmt.Sprintf("it is the turn of %s", g.crr)
+	default:
+		return "no stone is flipped"
+	}
+}
+
+// reject tells why the stone on p is illegal and waits until it is removed
+func (g *Game) reject(p Point, reason string) error {
+	fmt.Printf("ILLEGAL MOVE (%d, %d): %s\n", p[0], p[1], reason)
+	g.cellLog(p).WithFields(logrus.Fields{"player": g.crr.label(), "reason": reason}).Warn("Illegal move")
+
+	return g.waitRemoved(p)
+}
+
+// waitRemoved waits until the stone on p which is not on the board of the Game is removed.
+// Without the sensors, it waits for the stone lifted from p or RESUME typed to confirm the removal.
+// The wait is charged to the current Player, and it gives up when the time runs out.
+func (g *Game) waitRemoved(p Point) error {
+	g.clock.start(g.crr)
+	defer g.clock.pause()
+
+	if _, ok := g.m.(sensor); ok {
+		pos := g.Position()
+		return g.verify(&pos)
+	}
+
+	if !g.b.contains(p) || g.b[p[1]][p[0]] != NONE {
+		return nil
+	}
+
+	fmt.Printf("REMOVE THE STONE ON (%d, %d), OR TYPE resume WHEN IT IS REMOVED\n", p[0], p[1])
+
+	for {
+		q, err := g.readInput()
+
+		if err != nil || g.clock.flagged(g.crr) {
+			return err
+		}
+
+		switch {
+		case q.equal(resumePoint):
+			return nil
+		case q.equal(removePoint):
+			r, ok := g.m.(remover)
+
+			if !ok {
+				return nil
+			}
+
+			if x, y := r.Removed(); p.equal(Point{x, y}) {
+				return nil
+			}
+		}
+	}
+}
+
+// Illegals returns the number of illegal moves tried by pl
+func (g *Game) Illegals(pl Player) int {
+	return g.illegals[pl]
+}
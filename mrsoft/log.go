@@ -0,0 +1,65 @@
+package mrsoft
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// SetLogger sets the logger of the events of the Game
+func (g *Game) SetLogger(l logrus.FieldLogger) {
+	g.log = l.WithField("component", "mrsoft")
+}
+
+// moveLog returns the logger of the move p of pl
+func (g *Game) moveLog(pl Player, p Point) logrus.FieldLogger {
+	l := g.log.WithFields(logrus.Fields{
+		"player": pl.label(),
+		"ply":    len(g.history),
+	})
+
+	if p.equal(passPoint) {
+		return l
+	}
+
+	return l.WithFields(logrus.Fields{
+		"x":    p[0],
+		"y":    p[1],
+		"move": p.Notation(),
+	})
+}
+
+// cellLog returns the logger of the cell p
+func (g *Game) cellLog(p Point) logrus.FieldLogger {
+	return g.log.WithFields(logrus.Fields{
+		"x":    p[0],
+		"y":    p[1],
+		"move": p.Notation(),
+	})
+}
+
+// passTurn counts, logs, notifies and records the pass of the current Player
+func (g *Game) passTurn() {
+	pl := g.crr
+
+	countMove(pl, passPoint)
+	g.moveLog(pl, passPoint).Info("Pass")
+	g.pass()
+	g.notify(Event{Kind: PassEvent, Player: pl})
+}
+
+// finished counts, logs and notifies the finished Game
+func (g *Game) finished() {
+	g.countGame()
+
+	pos := g.Position()
+	black, white, _ := pos.Count()
+
+	g.notify(Event{Kind: FinishEvent, Winner: g.winner()})
+
+	g.log.WithFields(logrus.Fields{
+		"winner":  g.winner().label(),
+		"black":   black,
+		"white":   white,
+		"plies":   len(g.history),
+		"timeout": g.timeout != NONE,
+	}).Info("Game finished")
+}
@@ -0,0 +1,58 @@
+package mrsoft
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLogger(t *testing.T) {
+	g, err := NewGame(&dammyMiddleware{r: passMoves}, Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, hook := test.NewNullLogger()
+	g.SetLogger(l)
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	moves := []string{}
+
+	for _, e := range hook.AllEntries() {
+		if e.Data["component"] != "mrsoft" {
+			t.Errorf("%q is logged by component %v", e.Message, e.Data["component"])
+		}
+
+		switch e.Message {
+		case "Move":
+			moves = append(moves, e.Data["move"].(string))
+
+			if e.Data["x"] == nil || e.Data["y"] == nil {
+				t.Errorf("move %v is logged without the coordinate", e.Data["move"])
+			}
+		case "Pass":
+			moves = append(moves, "pa")
+		}
+	}
+
+	if s := FormatMoves(g.Record().Moves); s != strings.Join(moves, "") {
+		t.Fatalf("logged moves are %s, want %s", strings.Join(moves, ""), s)
+	}
+
+	l.SetLevel(logrus.WarnLevel)
+	hook.Reset()
+
+	if err = g.undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(hook.AllEntries()) != 0 {
+		t.Fatalf("%q is logged at the warn level", hook.LastEntry().Message)
+	}
+}
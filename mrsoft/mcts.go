@@ -0,0 +1,338 @@
+package mrsoft
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MCTS is an Engine using Monte Carlo Tree Search with UCT.
+// Each goroutine grows its own tree and the root statistics are merged at last.
+type MCTS struct {
+	// Playouts is the total number of playouts per move, used when TimeLimit is 0
+	Playouts int
+	// TimeLimit is the thinking time per move
+	TimeLimit time.Duration
+	// Parallel is the number of goroutines searching in parallel
+	Parallel int
+	// Exploration is the exploration constant of UCT
+	Exploration float64
+	// Biased makes playouts prefer corners and avoid X-squares
+	Biased bool
+	// Seed is the seed of random numbers, 0 means the current time
+	Seed int64
+}
+
+// NewMCTS returns a MCTS Engine with default settings
+func NewMCTS() *MCTS {
+	return &MCTS{
+		Playouts:    10000,
+		Parallel:    runtime.NumCPU(),
+		Exploration: math.Sqrt2,
+		Biased:      true,
+	}
+}
+
+type mctsNode struct {
+	parent   *mctsNode
+	children []*mctsNode
+	// move leading to this node, passPoint for pass
+	move Point
+	// Player who made the move
+	player Player
+	// moves not expanded yet
+	untried []Point
+	// sum of results from the view of player
+	wins   float64
+	visits int
+}
+
+func newMCTSNode(parent *mctsNode, move Point, pos *Position) *mctsNode {
+	n := &mctsNode{
+		parent:  parent,
+		move:    move,
+		player:  pos.crr.enemy(),
+		untried: pos.Moves(),
+	}
+
+	// the only move is pass if the game is not finished
+	if len(n.untried) == 0 && pos.hasMoves(pos.crr.enemy()) {
+		n.untried = []Point{passPoint}
+	}
+
+	return n
+}
+
+// selectChild returns the child which has maximum UCT value
+func (n *mctsNode) selectChild(c float64) (best *mctsNode) {
+	max := math.Inf(-1)
+	logN := math.Log(float64(n.visits))
+
+	for _, child := range n.children {
+		v := child.wins/float64(child.visits) + c*math.Sqrt(logN/float64(child.visits))
+
+		if v > max {
+			max, best = v, child
+		}
+	}
+
+	return
+}
+
+// MoveStat represents statistics of a move at the root of the search
+type MoveStat struct {
+	Move   Point
+	Visits int
+	// WinRate is the rate of winning playouts for the Player to move
+	WinRate float64
+}
+
+// Move returns the most visited move
+func (e *MCTS) Move(pos Position) (Point, error) {
+	stats, err := e.Search(pos)
+
+	if err != nil {
+		return Point{}, err
+	}
+
+	best := stats[0]
+	for _, s := range stats[1:] {
+		if s.Visits > best.Visits {
+			best = s
+		}
+	}
+
+	return best.Move, nil
+}
+
+// Search runs the tree search and returns statistics of each available move
+func (e *MCTS) Search(pos Position) ([]MoveStat, error) {
+	moves := pos.Moves()
+
+	switch len(moves) {
+	case 0:
+		return nil, errors.New("There is no available point")
+	case 1:
+		return []MoveStat{{Move: moves[0]}}, nil
+	}
+
+	parallel := e.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	seed := e.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	var deadline time.Time
+	if e.TimeLimit > 0 {
+		deadline = time.Now().Add(e.TimeLimit)
+	}
+
+	roots := make([]*mctsNode, parallel)
+
+	var wg sync.WaitGroup
+	for i := range roots {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			playouts := e.Playouts / parallel
+			if i < e.Playouts%parallel {
+				playouts++
+			}
+
+			roots[i] = e.grow(pos, playouts, deadline, rand.New(rand.NewSource(seed+int64(i))))
+		}(i)
+	}
+	wg.Wait()
+
+	stats := make([]MoveStat, len(moves))
+	wins := make([]float64, len(moves))
+
+	for i, m := range moves {
+		stats[i].Move = m
+
+		for _, root := range roots {
+			for _, child := range root.children {
+				if child.move.equal(m) {
+					stats[i].Visits += child.visits
+					wins[i] += child.wins
+				}
+			}
+		}
+
+		if stats[i].Visits != 0 {
+			stats[i].WinRate = wins[i] / float64(stats[i].Visits)
+		}
+	}
+
+	return stats, nil
+}
+
+// grow builds a tree from pos until the number of playouts or the deadline is reached
+func (e *MCTS) grow(pos Position, playouts int, deadline time.Time, r *rand.Rand) *mctsNode {
+	root := newMCTSNode(nil, passPoint, &pos)
+
+	for i := 0; ; i++ {
+		if deadline.IsZero() {
+			if i >= playouts {
+				break
+			}
+		} else if i%64 == 0 && time.Now().After(deadline) {
+			break
+		}
+
+		p := pos
+		n := root
+
+		// selection
+		for len(n.untried) == 0 && len(n.children) != 0 {
+			n = n.selectChild(e.Exploration)
+			p.move(n.move)
+		}
+
+		// expansion
+		if len(n.untried) != 0 {
+			k := r.Intn(len(n.untried))
+			m := n.untried[k]
+			n.untried = append(n.untried[:k], n.untried[k+1:]...)
+
+			p.move(m)
+			child := newMCTSNode(n, m, &p)
+			n.children = append(n.children, child)
+			n = child
+		}
+
+		// simulation
+		winner := e.playout(p, r)
+
+		// backpropagation
+		for ; n != nil; n = n.parent {
+			n.visits++
+
+			switch winner {
+			case n.player:
+				n.wins++
+			case NONE:
+				n.wins += 0.5
+			}
+		}
+	}
+
+	return root
+}
+
+// playout plays random moves until the end and returns the winner
+func (e *MCTS) playout(pos Position, r *rand.Rand) Player {
+	passed := false
+
+	for {
+		moves := pos.Moves()
+
+		if len(moves) == 0 {
+			if passed {
+				return pos.Winner()
+			}
+
+			passed = true
+			pos.pass()
+			continue
+		}
+
+		passed = false
+
+		if e.Biased {
+			pos.play(biasedChoice(&pos, moves, r))
+		} else {
+			pos.play(moves[r.Intn(len(moves))])
+		}
+	}
+}
+
+// Analyze returns the win rate of each available move in percent,
+// in descending order of visits
+func (e *MCTS) Analyze(pos Position) ([]MoveScore, error) {
+	stats, err := e.Search(pos)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Visits > stats[j].Visits
+	})
+
+	scores := make([]MoveScore, len(stats))
+
+	for i, s := range stats {
+		scores[i] = MoveScore{Move: s.Move, Score: int(100 * s.WinRate), Unit: PERCENT}
+	}
+
+	return scores, nil
+}
+
+// biasedChoice chooses a move weighting corners high and X-squares low.
+// Corners are weighted low by ANTI rules.
+func biasedChoice(pos *Position, moves []Point, r *rand.Rand) Point {
+	weights := make([]int, len(moves))
+	sum := 0
+	n := pos.b.size()
+
+	for i, m := range moves {
+		weights[i] = 4
+
+		switch {
+		case isCorner(m, n) && pos.rules == ANTI:
+			weights[i] = 1
+		case isCorner(m, n):
+			weights[i] = 16
+		case isXSquare(m, n) && pos.At(xSquareCorner(m, n)) == NONE:
+			weights[i] = 1
+		}
+
+		sum += weights[i]
+	}
+
+	k := r.Intn(sum)
+	for i, w := range weights {
+		if k < w {
+			return moves[i]
+		}
+		k -= w
+	}
+
+	return moves[len(moves)-1]
+}
+
+// isCorner reports whether p is a corner of n x n board
+func isCorner(p Point, n int) bool {
+	return (p[0] == 1 || p[0] == n) && (p[1] == 1 || p[1] == n)
+}
+
+// isXSquare reports whether p is diagonally next to a corner of n x n board
+func isXSquare(p Point, n int) bool {
+	return (p[0] == 2 || p[0] == n-1) && (p[1] == 2 || p[1] == n-1)
+}
+
+// xSquareCorner returns the corner next to the X-square p
+func xSquareCorner(p Point, n int) Point {
+	c := Point{1, 1}
+
+	if p[0] == n-1 {
+		c[0] = n
+	}
+
+	if p[1] == n-1 {
+		c[1] = n
+	}
+
+	return c
+}
@@ -0,0 +1,85 @@
+package mrsoft
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+)
+
+// placingMiddleware puts stones of engines by itself
+type placingMiddleware struct {
+	dammyMiddleware
+	// a number of placed stones
+	placed int
+}
+
+func (m *placingMiddleware) Place(x int, y int, pd mrmiddle.Pole) (err error) {
+	m.placed++
+
+	fmt.Printf("Place at (x, y) = (%d, %d)\n", x, y)
+
+	return
+}
+
+func TestMCTSMove(t *testing.T) {
+	g, err := NewGame(&dammyMiddleware{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.setAvailable()
+
+	e := NewMCTS()
+	e.Playouts = 400
+	e.Seed = 1
+
+	p, err := e.Move(g.Position())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(g.available[p]) == 0 {
+		t.Fatalf("MCTS chose unavailable point %v", p)
+	}
+}
+
+func TestMCTSSelfPlay(t *testing.T) {
+	m := &placingMiddleware{}
+
+	g, err := NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, p := range []Player{BLACK, WHITE} {
+		e := NewMCTS()
+		e.Playouts = 30
+		e.Parallel = 2
+		e.Seed = int64(i + 1)
+		g.SetEngine(p, e)
+	}
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// passes put no stone
+	moves := 0
+
+	for _, r := range g.history {
+		if !r.point.equal(passPoint) {
+			moves++
+		}
+	}
+
+	if m.placed != moves {
+		t.Fatalf("placed %d stones, but history has %d moves", m.placed, moves)
+	}
+
+	if !g.isFinish() {
+		t.Fatal("Game is not finished")
+	}
+}
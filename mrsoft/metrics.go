@@ -0,0 +1,67 @@
+package mrsoft
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	gamesPlayed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "magicreversi",
+		Subsystem: "game",
+		Name:      "games_total",
+		Help:      "Number of finished games by winner (black, white or draw).",
+	}, []string{"winner"})
+
+	movesPlayed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "magicreversi",
+		Subsystem: "game",
+		Name:      "moves_total",
+		Help:      "Number of moves by player and kind (put or pass).",
+	}, []string{"player", "kind"})
+
+	thinkTime = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "magicreversi",
+		Subsystem: "engine",
+		Name:      "think_seconds",
+		Help:      "Time the computer thinks per move by player.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"player"})
+)
+
+// RegisterMetrics registers the metrics of games to r
+func RegisterMetrics(r prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{gamesPlayed, movesPlayed, thinkTime} {
+		if err := r.Register(c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// label returns the metric label of the Player
+func (p Player) label() string {
+	if p == NONE {
+		return "draw"
+	}
+
+	return strings.ToLower(p.String())
+}
+
+// countMove counts a move of the Player, which is a pass if p is passPoint
+func countMove(pl Player, p Point) {
+	kind := "put"
+
+	if p.equal(passPoint) {
+		kind = "pass"
+	}
+
+	movesPlayed.WithLabelValues(pl.label(), kind).Inc()
+}
+
+// countGame counts the finished Game
+func (g *Game) countGame() {
+	gamesPlayed.WithLabelValues(g.winner().label()).Inc()
+}
@@ -0,0 +1,59 @@
+package mrsoft
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	m := &dammyMiddleware{}
+	g, err := NewGame(m, Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// play the first available move until the end
+	pos := g.Position()
+	var input [][2]int
+	puts := map[Player]float64{}
+
+	for !pos.IsFinish() {
+		moves := pos.Moves()
+
+		if len(moves) == 0 {
+			pos.pass()
+			continue
+		}
+
+		puts[pos.Turn()]++
+		input = append(input, [2]int(moves[0]))
+
+		if err = pos.Replay(moves[:1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	before := map[Player]float64{
+		BLACK: testutil.ToFloat64(movesPlayed.WithLabelValues("black", "put")),
+		WHITE: testutil.ToFloat64(movesPlayed.WithLabelValues("white", "put")),
+	}
+	games := testutil.ToFloat64(gamesPlayed.WithLabelValues(pos.Winner().label()))
+
+	m.r = input
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pl := range []Player{BLACK, WHITE} {
+		if n := testutil.ToFloat64(movesPlayed.WithLabelValues(pl.label(), "put")) - before[pl]; n != puts[pl] {
+			t.Errorf("%s put %v stones, want %v", pl, n, puts[pl])
+		}
+	}
+
+	if n := testutil.ToFloat64(gamesPlayed.WithLabelValues(pos.Winner().label())) - games; n != 1 {
+		t.Errorf("%v games are counted, want 1", n)
+	}
+}
@@ -3,8 +3,12 @@ package mrsoft
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/69guitar1015/MagicReversi/mrmiddle"
+	"github.com/sirupsen/logrus"
 )
 
 // this reversi system manages the board status
@@ -23,6 +27,11 @@ func (s *State) flip() {
 	*s = -1 * *s
 }
 
+// flipped returns the other color
+func (s State) flipped() State {
+	return -1 * s
+}
+
 func (s State) pole() mrmiddle.Pole {
 	return mrmiddle.Pole(s)
 }
@@ -71,9 +80,67 @@ func (b Point) equal(a Point) bool {
 	return a[0] == b[0] && a[1] == b[1]
 }
 
+// inputs which request commands
+var (
+	undoPoint   = Point{int(mrmiddle.UNDO), int(mrmiddle.UNDO)}
+	hintPoint   = Point{int(mrmiddle.HINT), int(mrmiddle.HINT)}
+	removePoint = Point{int(mrmiddle.REMOVE), int(mrmiddle.REMOVE)}
+	redoPoint   = Point{int(mrmiddle.REDO), int(mrmiddle.REDO)}
+	pausePoint  = Point{int(mrmiddle.PAUSE), int(mrmiddle.PAUSE)}
+	resumePoint = Point{int(mrmiddle.RESUME), int(mrmiddle.RESUME)}
+	newPoint    = Point{int(mrmiddle.NEWGAME), int(mrmiddle.NEWGAME)}
+)
+
+// ErrNoHistory is returned by undo when no move has been put
+var ErrNoHistory = errors.New("There is no move to undo")
+
 type direction [2]int
 
-type board [10][10]State
+// MaxSize is the maximum number of cells on a side of the board
+const MaxSize = 10
+
+// board is the grid of cells indexed by [y][x].
+// Cells from 1 to size on each side are surrounded by WALL.
+type board [MaxSize + 2][MaxSize + 2]State
+
+// newBoard returns a n x n board with 4 stones at the center
+func newBoard(n int) (b board, err error) {
+	if n < 4 || MaxSize < n || n%2 != 0 {
+		return b, fmt.Errorf("Board size must be 4, 6, 8 or 10, not %d", n)
+	}
+
+	for y := range b {
+		for x := range b[y] {
+			if 1 <= x && x <= n && 1 <= y && y <= n {
+				b[y][x] = NONE
+			} else {
+				b[y][x] = WALL
+			}
+		}
+	}
+
+	c := n / 2
+	b[c][c], b[c][c+1] = WHITE, BLACK
+	b[c+1][c], b[c+1][c+1] = BLACK, WHITE
+
+	return
+}
+
+// size returns the number of cells on a side
+func (b *board) size() (n int) {
+	for b[1][n+1] != WALL {
+		n++
+	}
+
+	return
+}
+
+// contains reports whether p is inside the board
+func (b *board) contains(p Point) bool {
+	n := b.size()
+
+	return 1 <= p[0] && p[0] <= n && 1 <= p[1] && p[1] <= n
+}
 
 func (b *board) put(p Point, c State) error {
 	if b[p[1]][p[0]] != NONE {
@@ -88,6 +155,45 @@ func (b *board) flip(p Point) {
 	b[p[1]][p[0]].flip()
 }
 
+// seekAvailable returns available points and their directions for the Player
+func (b *board) seekAvailable(pl Player) map[Point][]direction {
+	available := map[Point][]direction{}
+	n := b.size()
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			if b[y][x] != NONE {
+				continue
+			}
+
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					if dx == 0 && dy == 0 {
+						continue
+					}
+
+					if b[y+dy][x+dx] == pl.enemy().color() {
+						dist := 2
+						for {
+							if b[y+dist*dy][x+dist*dx] == pl.color() {
+								p := Point{x, y}
+								available[p] = append(available[p], direction{dx, dy})
+								break
+							} else if b[y+dist*dy][x+dist*dx] == pl.enemy().color() {
+								dist++
+							} else {
+								break
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return available
+}
+
 // reversi middleware interface
 type middleware interface {
 	Init() error
@@ -95,46 +201,126 @@ type middleware interface {
 	Flip(int, int, mrmiddle.Pole) error
 }
 
+// remover is implemented by middlewares which report lifted stones by REMOVE input
+type remover interface {
+	Removed() (int, int)
+}
+
 // PutRecord represents single record of put history
 type PutRecord struct {
 	point  Point
 	player Player
 	flips  []Point
+	// clock of the move and the main time left before it
+	time   MoveTime
+	before time.Duration
 }
 
 // Game represents whole reversi Game
 type Game struct {
 	// board object
 	b board
+	// Zobrist hash of the stones on the board
+	hash uint64
+	// position at the beginning of the Game
+	start Position
 	// current Player
 	crr Player
+	// rules deciding the winner
+	rules RuleSet
 	// middleware object
 	m middleware
 	// history of put stone
 	history []PutRecord
+	// variation tree of all moves tried and the node of the last move
+	tree, node *Variation
 	// available points
 	available map[Point][]direction
+	// engines playing instead of human
+	engines map[Player]Engine
+	// opening book
+	book *Book
+	// name of the current opening
+	opening string
+	// analyzer for hints
+	analyzer Analyzer
+	// reviewer for the post-game analysis
+	reviewer *Reviewer
+	// result of the post-game analysis
+	analysis *Analysis
+	// clock of the Game, nil if not timed
+	clock *Clock
+	// Player who lost on time, NONE otherwise
+	timeout Player
+	// numbers of illegal moves tried by each Player
+	illegals map[Player]int
+	// cells whose stones may be wrong since the coils failed
+	inconsistent map[Point]bool
+	// rater of the players, nil if the Game is not rated
+	rater Rater
+	// ratings returned by the rater and its error
+	ratings   string
+	rateError error
+	// logger of the events of the Game, the standard logger by default
+	log logrus.FieldLogger
+	// observer of the events, nil if not observed
+	observer Observer
+}
+
+// Option configures a Game in NewGame
+type Option func(*Game) error
+
+// Size makes the board n x n. n must be 4, 6, 8 or 10.
+func Size(n int) Option {
+	return func(g *Game) (err error) {
+		g.b, err = newBoard(n)
+		return
+	}
+}
+
+// sizer is implemented by middlewares which support only one board size
+type sizer interface {
+	Size() int
 }
 
-// NewGame returns a initial Game object
-func NewGame(m middleware) (g *Game) {
+// NewGame returns a initial Game object. The board is 8 x 8 unless Size is given
+// and the Game starts from the normal beginning unless StartFrom or Handicap is given.
+func NewGame(m middleware, opts ...Option) (g *Game, err error) {
+	b, _ := newBoard(8)
+
+	tree := &Variation{}
+
 	g = &Game{
-		b: board{
-			[10]State{WALL, WALL, WALL, WALL, WALL, WALL, WALL, WALL, WALL, WALL},
-			[10]State{WALL, NONE, NONE, NONE, NONE, NONE, NONE, NONE, NONE, WALL},
-			[10]State{WALL, NONE, NONE, NONE, NONE, NONE, NONE, NONE, NONE, WALL},
-			[10]State{WALL, NONE, NONE, NONE, NONE, NONE, NONE, NONE, NONE, WALL},
-			[10]State{WALL, NONE, NONE, NONE, WHITE, BLACK, NONE, NONE, NONE, WALL},
-			[10]State{WALL, NONE, NONE, NONE, BLACK, WHITE, NONE, NONE, NONE, WALL},
-			[10]State{WALL, NONE, NONE, NONE, NONE, NONE, NONE, NONE, NONE, WALL},
-			[10]State{WALL, NONE, NONE, NONE, NONE, NONE, NONE, NONE, NONE, WALL},
-			[10]State{WALL, NONE, NONE, NONE, NONE, NONE, NONE, NONE, NONE, WALL},
-			[10]State{WALL, WALL, WALL, WALL, WALL, WALL, WALL, WALL, WALL, WALL},
-		},
-		crr:       BLACK,
-		m:         m,
-		history:   []PutRecord{},
-		available: map[Point][]direction{},
+		b:            b,
+		crr:          BLACK,
+		m:            m,
+		history:      []PutRecord{},
+		tree:         tree,
+		node:         tree,
+		available:    map[Point][]direction{},
+		engines:      map[Player]Engine{},
+		illegals:     map[Player]int{},
+		inconsistent: map[Point]bool{},
+		analyzer:     NewEndgame(NewSearcher()),
+	}
+
+	g.SetLogger(logrus.StandardLogger())
+
+	for _, opt := range opts {
+		if err = opt(g); err != nil {
+			return nil, err
+		}
+	}
+
+	if s, ok := m.(sizer); ok && s.Size() != g.b.size() {
+		return nil, fmt.Errorf("Middleware supports only %dx%d board, not %dx%d", s.Size(), s.Size(), g.b.size(), g.b.size())
+	}
+
+	g.hash = g.b.zobrist()
+
+	// Resume has set the start
+	if len(g.history) == 0 {
+		g.start = g.Position()
 	}
 
 	return
@@ -142,35 +328,105 @@ func NewGame(m middleware) (g *Game) {
 
 // Start is game starting trigger
 func (g *Game) Start() (err error) {
+	if err = g.setup(); err != nil {
+		return fmt.Errorf("Failed to set up the board: %s", err)
+	}
+
+	g.notify(Event{Kind: StartEvent, Player: g.crr})
+
 	for {
 		g.printBoard()
 
+		g.printClock()
+
 		g.setAvailable()
 
+		g.printOpening()
+
 		if g.isFinish() {
 			fmt.Println("Finish!")
-			g.printSummary()
-			return
+
+			return g.finish()
 		}
 
 		// skip if Game is not finished and there is not available points
 		if len(g.available) == 0 {
 			fmt.Println("skipping")
-			g.crr = g.crr.enemy()
+			g.passTurn()
 			continue
 		}
 
-		x, y, err := g.m.GetInput()
+		var p Point
+
+		g.clock.start(g.crr)
+
+		if e, ok := g.engines[g.crr]; ok {
+			p, err = g.think(e)
+		} else {
+			p, err = g.getInput()
+		}
+
+		g.clock.pause()
 
 		if err != nil {
 			return fmt.Errorf("Failed to get input: %s", err)
 		}
 
-		p := Point{x, y}
+		if g.clock.flagged(g.crr) {
+			fmt.Printf("TIME OVER: %s\n", g.crr)
+
+			g.timeout = g.crr
+
+			return g.finish()
+		}
+
+		if p.equal(hintPoint) {
+			err = g.printHint()
 
-		if p[0] == -1 && p[1] == -1 {
+			if err != nil {
+				return fmt.Errorf("Failed to hint: %s", err)
+			}
+
+			continue
+		}
+
+		if p.equal(pausePoint) {
+			if p, err = g.pause(); err != nil {
+				return fmt.Errorf("Failed to pause: %s", err)
+			}
+
+			// NEWGAME while paused is handled below
+			if !p.equal(newPoint) {
+				continue
+			}
+		}
+
+		// RESUME without PAUSE
+		if p.equal(resumePoint) {
+			continue
+		}
+
+		if p.equal(newPoint) {
+			if err = g.Restart(); err == nil {
+				err = g.setup()
+			}
+
+			if err != nil {
+				return fmt.Errorf("Failed to start a new game: %s", err)
+			}
+
+			g.notify(Event{Kind: StartEvent, Player: g.crr})
+			continue
+		}
+
+		if p.equal(undoPoint) {
 			// undo when (x, y) == (-1, -1)
-			err = g.undo()
+			err = g.takeBack()
+
+			if err == ErrNoHistory {
+				fmt.Println(err)
+				continue
+			}
 
 			if err != nil {
 				return fmt.Errorf("Failed to undo: %s", err)
@@ -179,52 +435,85 @@ func (g *Game) Start() (err error) {
 			continue
 		}
 
+		if p.equal(redoPoint) {
+			err = g.replayForward()
+
+			if err == ErrNoRedo {
+				fmt.Println(err)
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("Failed to redo: %s", err)
+			}
+
+			continue
+		}
+
+		if reason := g.illegalReason(p); reason != "" {
+			g.illegals[g.crr]++
+
+			if err = g.reject(p, reason); err != nil {
+				return fmt.Errorf("Failed to wait for the stone removed: %s", err)
+			}
+
+			continue
+		}
+
 		err = g.put(p)
 
 		if err != nil {
-			return fmt.Errorf("Failed to put the stone: %s", err)
+			fmt.Printf("Failed to put the stone: %s\n", err)
+
+			if err = g.waitRemoved(p); err != nil {
+				return fmt.Errorf("Failed to wait for the stone removed: %s", err)
+			}
+
+			continue
 		}
 
 		g.crr = g.crr.enemy()
 	}
 }
 
-// seek available Point
-func (g *Game) seekAvailable() map[Point][]direction {
-	available := map[Point][]direction{}
+// getInput waits for input of the current Player.
+// Lifting the last put stone requests UNDO and other lifted stones must be put back.
+func (g *Game) getInput() (p Point, err error) {
+	for {
+		p, err = g.readInput()
 
-	for y := 1; y <= 8; y++ {
-		for x := 1; x <= 8; x++ {
-			if g.b[y][x] != NONE {
-				continue
-			}
+		if err != nil || !p.equal(removePoint) {
+			return
+		}
 
-			for dy := -1; dy <= 1; dy++ {
-				for dx := -1; dx <= 1; dx++ {
-					if dx == 0 && dy == 0 {
-						continue
-					}
+		r, ok := g.m.(remover)
 
-					if g.b[y+dy][x+dx] == g.crr.enemy().color() {
-						dist := 2
-						for {
-							if g.b[y+dist*dy][x+dist*dx] == g.crr.color() {
-								p := Point{x, y}
-								available[p] = append(available[p], direction{dx, dy})
-								break
-							} else if g.b[y+dist*dy][x+dist*dx] == g.crr.enemy().color() {
-								dist++
-							} else {
-								break
-							}
-						}
-					}
-				}
+		if !ok {
+			continue
+		}
+
+		x, y := r.Removed()
+		q := Point{x, y}
+
+		if len(g.history) != 0 && q.equal(g.history[len(g.history)-1].point) {
+			return undoPoint, nil
+		}
+
+		fmt.Printf("PUT THE STONE BACK ON (%d, %d)\n", x, y)
+
+		for !p.equal(q) {
+			p, err = g.readInput()
+
+			if err != nil || g.clock.flagged(g.crr) {
+				return
 			}
 		}
 	}
+}
 
-	return available
+// seek available Point
+func (g *Game) seekAvailable() map[Point][]direction {
+	return g.b.seekAvailable(g.crr)
 }
 
 func (g *Game) setAvailable() {
@@ -233,6 +522,10 @@ func (g *Game) setAvailable() {
 
 // put a stone to (x, y) address on the board
 func (g *Game) put(p Point) (err error) {
+	if !g.b.contains(p) {
+		return fmt.Errorf("(%d, %d) is out of the board", p[0], p[1])
+	}
+
 	// return error if the Point is not available
 	if len(g.available[p]) == 0 {
 		return errors.New("Can't put stones there")
@@ -241,70 +534,203 @@ func (g *Game) put(p Point) (err error) {
 	pr := PutRecord{
 		point:  p,
 		player: g.crr,
-		flips:  []Point{},
+		flips:  g.flips(p),
 	}
 
-	err = g.b.put(p, g.crr.color())
+	// physical flips first, the board is untouched if they fail
+	if err = g.flipAll(pr.flips, g.crr.color()); err != nil {
+		return
+	}
 
-	if err != nil {
+	if err = g.b.put(p, g.crr.color()); err != nil {
 		return
 	}
 
-	for _, d := range g.available[p] {
-		dist := 1
-		for {
-			dp := Point{p[0] + dist*d[0], p[1] + dist*d[1]}
-			if g.b[dp[1]][dp[0]] == g.crr.color() {
-				break
-			} else if g.b[dp[1]][dp[0]] == g.crr.enemy().color() {
+	g.hash ^= zobristStone(p, g.crr.color())
 
-				err = g.flip(dp, g.crr.color())
+	for _, dp := range pr.flips {
+		g.b.flip(dp)
+		g.hash ^= zobristFlip(dp)
+	}
 
-				if err != nil {
-					return
-				}
+	pr.time, pr.before = g.clock.finish(g.crr)
 
-				// append to flip record
-				pr.flips = append(pr.flips, dp)
-			} else {
-				return errors.New("Can't available this direction")
+	g.history = append(g.history, pr)
+	g.node = g.node.child(p)
+
+	countMove(pr.player, p)
+	g.moveLog(pr.player, p).WithField("flips", len(pr.flips)).Info("Move")
+	g.notify(Event{Kind: MoveEvent, Player: pr.player, Point: p, Flips: pr.flips})
+
+	return
+}
+
+// pass records the pass of the current Player who has no available point
+func (g *Game) pass() {
+	pr := PutRecord{
+		point:  passPoint,
+		player: g.crr,
+	}
+
+	// passing takes no time
+	if g.clock != nil {
+		pr.before = g.clock.Left(g.crr)
+		pr.time.Left = pr.before
+	}
+
+	g.history = append(g.history, pr)
+	g.node = g.node.child(passPoint)
+
+	g.crr = g.crr.enemy()
+}
+
+// flips returns the stones flipped by putting a stone on available p
+func (g *Game) flips(p Point) (flips []Point) {
+	for _, d := range g.available[p] {
+		for dist := 1; ; dist++ {
+			dp := Point{p[0] + dist*d[0], p[1] + dist*d[1]}
+
+			if g.b[dp[1]][dp[0]] != g.crr.enemy().color() {
+				break
 			}
 
-			dist++
+			flips = append(flips, dp)
 		}
 	}
 
-	g.history = append(g.history, pr)
+	return
+}
+
+// flipAll flips stones on ps to s by the coils.
+// If a flip fails, the flipped stones are flipped back
+// and the stones which can't be flipped back are marked inconsistent.
+func (g *Game) flipAll(ps []Point, s State) (err error) {
+	for i, p := range ps {
+		if err = g.m.Flip(p[0], p[1], s.pole()); err != nil {
+			g.cellLog(p).WithError(err).Error("Failed to flip")
+			g.notify(Event{Kind: FaultEvent, Player: g.crr, Point: p, Err: err})
+			err = fmt.Errorf("Failed to flip (%d, %d): %s", p[0], p[1], err)
+			g.rollback(ps[:i], s)
+			return
+		}
+
+		delete(g.inconsistent, p)
+	}
 
 	return
 }
 
-func (g *Game) flip(p Point, s State) (err error) {
-	// simulational flip
-	g.b.flip(p)
-	// physical flip
-	err = g.m.Flip(p[0], p[1], s.pole())
+// rollback flips stones on ps flipped to s back in reverse order
+func (g *Game) rollback(ps []Point, s State) {
+	for i := len(ps) - 1; i >= 0; i-- {
+		p := ps[i]
+
+		if err := g.m.Flip(p[0], p[1], s.flipped().pole()); err != nil {
+			g.cellLog(p).WithError(err).Error("Failed to flip back, the stone may be wrong")
+			g.notify(Event{Kind: FaultEvent, Player: g.crr, Point: p, Err: err})
+			g.inconsistent[p] = true
+		}
+	}
+}
+
+// Inconsistent returns the cells whose stones may differ from the board of the Game
+func (g *Game) Inconsistent() (ps []Point) {
+	for p := range g.inconsistent {
+		ps = append(ps, p)
+	}
+
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i][1] < ps[j][1] || ps[i][1] == ps[j][1] && ps[i][0] < ps[j][0]
+	})
 
 	return
 }
 
+// undo takes back the last move and reverses its flips
 func (g *Game) undo() (err error) {
+	if len(g.history) == 0 {
+		return ErrNoHistory
+	}
+
 	record := g.history[len(g.history)-1]
+
+	// re-flip backwards
+	backwards := make([]Point, len(record.flips))
+
+	for i, p := range record.flips {
+		backwards[len(backwards)-i-1] = p
+	}
+
+	if err = g.flipAll(backwards, record.player.enemy().color()); err != nil {
+		return
+	}
+
 	g.history = g.history[:len(g.history)-1]
+	g.node = g.node.parent
 
-	g.b[record.point[1]][record.point[0]] = NONE
+	if !record.point.equal(passPoint) {
+		g.b[record.point[1]][record.point[0]] = NONE
+		g.hash ^= zobristStone(record.point, record.player.color())
+	}
 
-	for i := range record.flips {
-		// re-flip backwards
-		p := record.flips[len(record.flips)-i-1]
-		err = g.flip(p, record.player.enemy().color())
+	for _, p := range record.flips {
+		g.b.flip(p)
+		g.hash ^= zobristFlip(p)
+	}
 
-		if err != nil {
-			return fmt.Errorf("Failed to flip: %s", err)
+	g.crr = record.player
+
+	g.clock.restore(record.player, record.before)
+
+	g.moveLog(record.player, record.point).Info("Undo")
+	g.notify(Event{Kind: UndoEvent, Player: record.player, Point: record.point})
+
+	return
+}
+
+// takeBack undoes the last move and the replies of Engines,
+// then waits until the undone stones are removed from the board
+func (g *Game) takeBack() (err error) {
+	undone := []Point{}
+
+	for {
+		if len(g.history) == 0 {
+			return ErrNoHistory
+		}
+
+		last := g.history[len(g.history)-1]
+
+		if err = g.undo(); err != nil {
+			return
+		}
+
+		if !last.point.equal(passPoint) {
+			undone = append(undone, last.point)
+		}
+
+		if len(g.history) == 0 {
+			break
+		}
+
+		// a pass is forced, so take back the move before it as well
+		if last.point.equal(passPoint) {
+			continue
+		}
+
+		// take back the Engine's reply as well
+		if !g.hasHuman() || g.engines[g.crr] == nil {
+			break
 		}
 	}
 
-	g.crr = record.player
+	if _, ok := g.m.(sensor); ok {
+		pos := g.Position()
+		return g.verify(&pos)
+	}
+
+	for _, p := range undone {
+		fmt.Printf("REMOVE THE STONE ON (%d, %d)\n", p[0], p[1])
+	}
 
 	return
 }
@@ -326,8 +752,10 @@ func (g *Game) isFinish() bool {
 }
 
 func (g *Game) printBoard() {
-	for i, row := range g.b {
-		for j, v := range row {
+	n := g.b.size()
+
+	for i, row := range g.b[:n+2] {
+		for j, v := range row[:n+2] {
 			switch v {
 			case BLACK:
 				fmt.Printf("○\t")
@@ -337,7 +765,7 @@ func (g *Game) printBoard() {
 				fmt.Printf(" \t")
 			case WALL:
 				switch {
-				case j == 0 || j == 9:
+				case j == 0 || j == n+1:
 					fmt.Printf("%d\t", i)
 				default:
 					fmt.Printf("%d\t", j)
@@ -346,38 +774,92 @@ func (g *Game) printBoard() {
 		}
 		fmt.Printf("\n")
 	}
+
+	for _, p := range g.Inconsistent() {
+		fmt.Printf("PLEASE CHECK THE STONE ON (%d, %d) IS %s\n", p[0], p[1], g.b[p[1]][p[0]])
+	}
+}
+
+// winner returns the winner of the finished Game, NONE on draw
+func (g *Game) winner() Player {
+	if g.timeout != NONE {
+		return g.timeout.enemy()
+	}
+
+	pos := g.Position()
+
+	return pos.Winner()
+}
+
+// finish ends the Game: it is counted, reviewed, rated and summarized
+func (g *Game) finish() error {
+	g.finished()
+
+	if err := g.review(); err != nil {
+		return fmt.Errorf("Failed to analyze: %s", err)
+	}
+
+	g.rate()
+	g.printSummary()
+
+	return nil
 }
 
 // print the Game summary
 func (g *Game) printSummary() {
 	fmt.Println("# SUMMARY ###########################################################")
 
-	counts := map[State]int{BLACK: 0, WHITE: 0, NONE: 0}
-
-	for i := 0; i <= 8; i++ {
-		for j := 0; j <= 8; j++ {
-			counts[g.b[i][j]]++
-		}
-	}
+	pos := g.Position()
+	black, white, blank := pos.Count()
+	counts := map[State]int{BLACK: black, WHITE: white, NONE: blank}
 
-	switch {
-	case counts[BLACK] == counts[WHITE]:
+	switch winner := g.winner(); {
+	case winner == NONE:
 		fmt.Println("DRAW")
+	case g.timeout != NONE:
+		fmt.Printf("%s PLAYER WINS ON TIME!\n", winner)
 	default:
-		fmt.Printf("%s PLAYER WINS!\n", Player(BLACK))
+		fmt.Printf("%s PLAYER WINS!\n", winner)
+	}
+
+	if g.rules != STANDARD {
+		fmt.Printf("RULES:\t\t\t%s\n", strings.ToUpper(g.rules.String()))
 	}
 
 	fmt.Printf("NUMBER OF BLACK STONE:\t%2d\n", counts[BLACK])
 	fmt.Printf("NUMBER OF WHITE STONE:\t%2d\n", counts[WHITE])
 	fmt.Printf("NUMBER OF BLANK SPACE:\t%2d\n", counts[NONE])
 
+	if g.opening != "" {
+		fmt.Printf("OPENING:\t\t%s\n", g.opening)
+	}
+
+	if g.illegals[BLACK] != 0 || g.illegals[WHITE] != 0 {
+		fmt.Printf("ILLEGAL MOVES:\t\tBLACK %d, WHITE %d\n", g.illegals[BLACK], g.illegals[WHITE])
+	}
+
 	fmt.Printf("\n# KIFU\n")
 	for i, record := range g.history {
-		fmt.Printf("[%2d]\t(%d, %d)\t%s\t", i+1, record.point[0], record.point[1], record.player)
+		if record.point.equal(passPoint) {
+			fmt.Printf("[%2d]\tPASS\t%s\t", i+1, record.player)
+		} else {
+			fmt.Printf("[%2d]\t(%d, %d)\t%s\t", i+1, record.point[0], record.point[1], record.player)
+		}
+		if g.clock != nil {
+			fmt.Printf("%s\t", record.time.Used.Round(time.Second))
+		}
 		if (i+1)%3 == 0 {
 			fmt.Printf("\n")
 		}
 	}
 
+	fmt.Printf("\n\n# TRANSCRIPT\n%s\n", g.Record().Transcript())
+
+	g.printVariations()
+
+	g.printAnalysis()
+
+	g.printRatings()
+
 	fmt.Println("#####################################################################")
 }
@@ -120,11 +120,171 @@ func Test(t *testing.T) {
 
 	m.Init()
 
-	g := NewGame(m)
+	g, err := NewGame(m)
 
-	err := g.Start()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = g.Start()
 
 	if err != nil {
 		log.Fatal(err)
 	}
 }
+
+// liftingMiddleware reports stones lifted by REMOVE input
+type liftingMiddleware struct {
+	dammyMiddleware
+	// lifted stones in order
+	lifted []Point
+}
+
+func (m *liftingMiddleware) Removed() (int, int) {
+	p := m.lifted[0]
+	m.lifted = m.lifted[1:]
+
+	return p[0], p[1]
+}
+
+func TestUndo(t *testing.T) {
+	r := int(mrmiddle.REMOVE)
+
+	m := &liftingMiddleware{
+		dammyMiddleware: dammyMiddleware{
+			r: [][2]int{
+				// nothing to undo
+				{-1, -1},
+				{3, 4},
+				// lift a stone other than the last one and put it back
+				{r, r},
+				{4, 4},
+				// lift the last stone
+				{r, r},
+			},
+		},
+		lifted: []Point{{4, 4}, {3, 4}},
+	}
+
+	g, err := NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.undo(); err != ErrNoHistory {
+		t.Fatalf("undo returns %v on empty history", err)
+	}
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	pos := g.Position()
+
+	if len(g.history) != 0 || g.crr != BLACK || pos.key() != g.start.key() {
+		t.Fatalf("%d moves are left after undo and %s to move", len(g.history), g.crr)
+	}
+}
+
+func TestIllegalMove(t *testing.T) {
+	remove, resume := int(mrmiddle.REMOVE), int(mrmiddle.RESUME)
+
+	// the illegal stones are lifted or confirmed to be removed by RESUME,
+	// and d4 is already on the board
+	m := &dammyMiddleware{
+		r: [][2]int{
+			{1, 1},
+			{3, 4},
+			{remove, remove},
+			{4, 4},
+			{5, 3},
+			{resume, resume},
+			{3, 4},
+		},
+	}
+
+	g, err := NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.setAvailable()
+
+	for p, want := range map[Point]string{
+		{1, 1}: "no stone is flipped",
+		{4, 4}: "there is already a stone",
+		{5, 3}: "it is the turn of BLACK",
+		{9, 1}: "out of the board",
+		{3, 4}: "",
+	} {
+		if reason := g.illegalReason(p); reason != want {
+			t.Fatalf("(%d, %d) is illegal because %q, want %q", p[0], p[1], reason, want)
+		}
+	}
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	// c4 put before a1 is removed is not played
+	if len(g.history) != 1 || g.Illegals(BLACK) != 3 || g.Illegals(WHITE) != 0 {
+		t.Fatalf("%d moves are put after %d illegal moves", len(g.history), g.Illegals(BLACK))
+	}
+}
+
+// failingMiddleware fails to flip at the given numbers of calls
+type failingMiddleware struct {
+	dammyMiddleware
+	// number of Flip calls and the calls which fail
+	calls int
+	fails map[int]bool
+}
+
+func (m *failingMiddleware) Flip(x int, y int, pd mrmiddle.Pole) (err error) {
+	m.calls++
+
+	if m.fails[m.calls] {
+		return errors.New("Coil is broken")
+	}
+
+	return m.dammyMiddleware.Flip(x, y, pd)
+}
+
+func TestPutRollback(t *testing.T) {
+	m := &failingMiddleware{
+		dammyMiddleware: dammyMiddleware{r: [][2]int{{3, 4}, {int(mrmiddle.REMOVE), int(mrmiddle.REMOVE)}, {3, 4}}},
+		fails:           map[int]bool{1: true},
+	}
+
+	g, err := NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	// the first try fails and the second one succeeds
+	if len(g.history) != 1 || g.b[4][4] != BLACK {
+		t.Fatalf("%d moves are put and (4, 4) is %s", len(g.history), g.b[4][4])
+	}
+
+	// the second flip fails and the first one can't be flipped back
+	m.calls, m.fails = 0, map[int]bool{2: true, 3: true}
+
+	if err = g.flipAll([]Point{{1, 1}, {2, 2}}, BLACK); err == nil {
+		t.Fatal("flipAll succeeds with broken coils")
+	}
+
+	if ps := g.Inconsistent(); len(ps) != 1 || !ps[0].equal(Point{1, 1}) {
+		t.Fatalf("inconsistent cells are %v, want (1, 1)", ps)
+	}
+
+	if err = g.flipAll([]Point{{1, 1}}, BLACK); err != nil || len(g.Inconsistent()) != 0 {
+		t.Fatalf("(1, 1) is still inconsistent after flipped: %v", err)
+	}
+}
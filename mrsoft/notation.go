@@ -0,0 +1,86 @@
+package mrsoft
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Notation returns p in the standard notation such as "f5", or "pa" for a pass
+func (p Point) Notation() string {
+	if p.equal(passPoint) {
+		return "pa"
+	}
+
+	return fmt.Sprintf("%c%d", 'a'+p[0]-1, p[1])
+}
+
+// ParsePoint parses the standard notation such as "f5", or "pa" for a pass
+func ParsePoint(s string) (Point, error) {
+	s = strings.ToLower(s)
+
+	if s == "pa" {
+		return passPoint, nil
+	}
+
+	if len(s) < 2 || s[0] < 'a' || 'a'+MaxSize-1 < s[0] || s[1] < '0' || '9' < s[1] {
+		return Point{}, fmt.Errorf("Invalid point: %q", s)
+	}
+
+	y, err := strconv.Atoi(s[1:])
+
+	if err != nil || y < 1 || MaxSize < y {
+		return Point{}, fmt.Errorf("Invalid point: %q", s)
+	}
+
+	return Point{int(s[0]-'a') + 1, y}, nil
+}
+
+// ParseMoves parses a move sequence such as "f5d6c3" or "f5 d6 c3"
+func ParseMoves(s string) (moves []Point, err error) {
+	s = strings.Join(strings.Fields(s), "")
+
+	for i := 0; i < len(s); {
+		// a move is a letter followed by digits, or "pa"
+		j := i + 1
+		if strings.HasPrefix(strings.ToLower(s[i:]), "pa") {
+			j++
+		}
+		for j < len(s) && '0' <= s[j] && s[j] <= '9' {
+			j++
+		}
+
+		p, err := ParsePoint(s[i:j])
+
+		if err != nil {
+			return nil, err
+		}
+
+		moves = append(moves, p)
+		i = j
+	}
+
+	return
+}
+
+// FormatMoves returns the move sequence such as "f5d6c3"
+func FormatMoves(moves []Point) string {
+	s := make([]string, len(moves))
+
+	for i, p := range moves {
+		s[i] = p.Notation()
+	}
+
+	return strings.Join(s, "")
+}
+
+// MarshalText encodes p in the standard notation
+func (p Point) MarshalText() ([]byte, error) {
+	return []byte(p.Notation()), nil
+}
+
+// UnmarshalText decodes p from the standard notation
+func (p *Point) UnmarshalText(text []byte) (err error) {
+	*p, err = ParsePoint(string(text))
+	return
+}
@@ -0,0 +1,107 @@
+package mrsoft
+
+import (
+	"testing"
+	"time"
+)
+
+// a2 a3 c4 a1 on 4 x 4 board makes BLACK pass
+var passMoves = [][2]int{{1, 2}, {1, 3}, {3, 4}, {1, 1}}
+
+func TestPassRecord(t *testing.T) {
+	g, err := NewGame(&dammyMiddleware{r: passMoves}, Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	r := g.Record()
+
+	if s := FormatMoves(r.Moves); s != "a2a3c4a1pa" {
+		t.Fatalf("moves are %s, want a2a3c4a1pa", s)
+	}
+
+	parsed, err := ParseTranscript(r.Transcript())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if FormatMoves(parsed.Moves) != FormatMoves(r.Moves) {
+		t.Fatalf("transcript is parsed as %s", FormatMoves(parsed.Moves))
+	}
+
+	resumed, err := NewGame(&dammyMiddleware{}, Resume(r))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pos, rpos := g.Position(), resumed.Position()
+
+	if len(resumed.history) != len(g.history) || rpos.key() != pos.key() || resumed.start.key() != g.start.key() {
+		t.Fatalf("resumed Game is %s after %d moves, want %s", rpos.Diagram(), len(resumed.history), pos.Diagram())
+	}
+
+	if err = pos.Replay([]Point{passPoint}); err == nil {
+		t.Fatal("WHITE can pass with available points")
+	}
+}
+
+func TestUndoPass(t *testing.T) {
+	g, err := NewGame(&dammyMiddleware{r: append(passMoves, [2]int{-1, -1})}, Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	// the pass of BLACK and a1 are taken back
+	if len(g.history) != 3 || g.crr != WHITE {
+		t.Fatalf("%d moves are left and %s to move", len(g.history), g.crr)
+	}
+}
+
+func TestResumePassClock(t *testing.T) {
+	g, err := NewGame(&dammyMiddleware{r: passMoves}, Size(4), Timed(TimeControl{Main: time.Minute}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	r := g.Record()
+
+	if len(r.Times) != 5 {
+		t.Fatalf("Record has %d clocks, want 5 with the pass", len(r.Times))
+	}
+
+	// BLACK plays a2 and c4, then passes
+	r.Times[0].Left, r.Times[2].Left, r.Times[4].Left = 40*time.Second, 20*time.Second, 20*time.Second
+
+	// the pass is recorded or omitted with its clock
+	omitted := *r
+	omitted.Moves, omitted.Times = r.Moves[:4], r.Times[:4]
+
+	for _, rec := range []*Record{r, &omitted} {
+		resumed, err := NewGame(&dammyMiddleware{}, Size(4), Timed(TimeControl{Main: time.Minute}), Resume(rec))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if left := resumed.Clock().Left(BLACK); left != 20*time.Second {
+			t.Fatalf("BLACK has %s after resuming %s, want 20s", left, FormatMoves(rec.Moves))
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package mrsoft
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Generator is a move generator verified by Perft
+type Generator struct {
+	Name string
+	// MaxSize is the largest board size supported
+	MaxSize int
+	// count returns the number of leaves of pos at depth
+	count func(pos Position, depth int) uint64
+}
+
+// Generators of available moves
+var (
+	// SeekGenerator uses seekAvailable of the Game
+	SeekGenerator = &Generator{Name: "seek", MaxSize: MaxSize, count: perftSeek}
+	// PositionGenerator uses Position of the engines
+	PositionGenerator = &Generator{Name: "position", MaxSize: MaxSize, count: perftPosition}
+	// BitboardGenerator uses bit operations on boards up to 8 x 8
+	BitboardGenerator = &Generator{Name: "bitboard", MaxSize: 8, count: perftBitboard}
+)
+
+// Generators lists all Generators
+var Generators = []*Generator{SeekGenerator, PositionGenerator, BitboardGenerator}
+
+// ParseGenerator returns the Generator of the name
+func ParseGenerator(name string) (*Generator, error) {
+	for _, gen := range Generators {
+		if gen.Name == name {
+			return gen, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Unknown generator: %q", name)
+}
+
+// PerftResult is the number of leaves after a root move
+type PerftResult struct {
+	// Move is passPoint when the root Player passes
+	Move  Point
+	Nodes uint64
+}
+
+// Perft returns the number of leaves of the game tree of pos at depth.
+// A pass is a ply, and a finished game before depth is a leaf.
+func Perft(pos Position, depth int, gen *Generator) (uint64, error) {
+	if n := pos.Size(); n > gen.MaxSize {
+		return 0, fmt.Errorf("%s generator supports boards up to %dx%d, not %dx%d", gen.Name, gen.MaxSize, gen.MaxSize, n, n)
+	}
+
+	return gen.count(pos, depth), nil
+}
+
+// Divide returns the number of leaves after each root move in the order of (x, y)
+func Divide(pos Position, depth int, gen *Generator) ([]PerftResult, error) {
+	if depth < 1 || pos.IsFinish() {
+		return nil, nil
+	}
+
+	moves := pos.Moves()
+	if len(moves) == 0 {
+		moves = []Point{passPoint}
+	}
+
+	sort.Slice(moves, func(i, j int) bool {
+		return moves[i][0] < moves[j][0] || moves[i][0] == moves[j][0] && moves[i][1] < moves[j][1]
+	})
+
+	results := make([]PerftResult, len(moves))
+
+	for i, m := range moves {
+		child := pos
+		child.move(m)
+
+		nodes, err := Perft(child, depth-1, gen)
+
+		if err != nil {
+			return nil, err
+		}
+
+		results[i] = PerftResult{Move: m, Nodes: nodes}
+	}
+
+	return results, nil
+}
+
+// perftSeek counts leaves by seekAvailable and flips along its directions
+func perftSeek(pos Position, depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+
+	available := pos.b.seekAvailable(pos.crr)
+
+	if len(available) == 0 {
+		if len(pos.b.seekAvailable(pos.crr.enemy())) == 0 {
+			return 1
+		}
+
+		pos.crr = pos.crr.enemy()
+
+		return perftSeek(pos, depth-1)
+	}
+
+	var nodes uint64
+
+	for p, dirs := range available {
+		child := pos
+		child.b[p[1]][p[0]] = pos.crr.color()
+
+		for _, d := range dirs {
+			for q := (Point{p[0] + d[0], p[1] + d[1]}); child.b[q[1]][q[0]] != pos.crr.color(); q = (Point{q[0] + d[0], q[1] + d[1]}) {
+				child.b.flip(q)
+			}
+		}
+
+		child.crr = pos.crr.enemy()
+		nodes += perftSeek(child, depth-1)
+	}
+
+	return nodes
+}
+
+// perftPosition counts leaves by play and unplay of Position
+func perftPosition(pos Position, depth int) uint64 {
+	return pos.perft(depth)
+}
+
+func (pos *Position) perft(depth int) uint64 {
+	if depth == 0 {
+		return 1
+	}
+
+	moves := pos.Moves()
+
+	if len(moves) == 0 {
+		if !pos.hasMoves(pos.crr.enemy()) {
+			return 1
+		}
+
+		pos.pass()
+		nodes := pos.perft(depth - 1)
+		pos.pass()
+
+		return nodes
+	}
+
+	var nodes uint64
+
+	for _, m := range moves {
+		flips := pos.play(m)
+		nodes += pos.perft(depth - 1)
+		pos.unplay(m, flips)
+	}
+
+	return nodes
+}
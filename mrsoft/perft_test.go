@@ -0,0 +1,108 @@
+package mrsoft
+
+import (
+	"strings"
+	"testing"
+)
+
+// perftCounts are the numbers of leaves from the start of 8 x 8 board at depth 1, 2, ...
+var perftCounts = []uint64{4, 12, 56, 244, 1396, 8200, 55092, 390216, 3005288}
+
+func TestPerft(t *testing.T) {
+	for _, gen := range Generators {
+		depth := len(perftCounts)
+
+		// slower generators are checked to smaller depth
+		if gen != BitboardGenerator {
+			depth = 6
+		}
+
+		for d := 1; d <= depth; d++ {
+			nodes, err := Perft(InitialPosition(), d, gen)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if nodes != perftCounts[d-1] {
+				t.Fatalf("%s perft(%d) = %d, want %d", gen.Name, d, nodes, perftCounts[d-1])
+			}
+		}
+	}
+}
+
+func TestPerftPasses(t *testing.T) {
+	// 4 x 4 games pass and finish before the depth
+	pos, err := NewPosition(4)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := Perft(pos, 16, SeekGenerator)
+
+	for _, gen := range Generators[1:] {
+		if nodes, _ := Perft(pos, 16, gen); nodes != want {
+			t.Fatalf("%s perft(16) of 4x4 = %d, want %d", gen.Name, nodes, want)
+		}
+	}
+
+	// the position after the pass of BLACK
+	moves, _ := ParseMoves("a2a3c4a1")
+	pos.Replay(moves)
+
+	results, err := Divide(pos, 3, BitboardGenerator)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 1 || !results[0].Move.equal(passPoint) {
+		t.Fatalf("Divide returns %v, want only a pass", results)
+	}
+
+	big, _ := NewPosition(10)
+
+	if _, err = Perft(big, 1, BitboardGenerator); err == nil {
+		t.Fatal("bitboard generator accepts 10x10 board")
+	}
+}
+
+func TestDivide(t *testing.T) {
+	results, err := Divide(InitialPosition(), 5, PositionGenerator)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sum uint64
+
+	for _, r := range results {
+		sum += r.Nodes
+	}
+
+	// all 4 moves are symmetric
+	if len(results) != 4 || sum != perftCounts[4] || results[0].Move.Notation() != "c4" || results[0].Nodes != sum/4 {
+		t.Fatalf("Divide returns %v", results)
+	}
+}
+
+func TestDivideOrder(t *testing.T) {
+	// BLACK can put on a2 and a10, whose notations are in the other order
+	rows := []string{"----------", "----------", "O---------", "X---------", "----------", "----------", "----------", "X---------", "O---------", "----------"}
+	pos, err := ParseBoard(strings.Join(rows, "") + "X")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := Divide(pos, 1, PositionGenerator)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 2 || results[0].Move != (Point{1, 2}) || results[1].Move != (Point{1, 10}) {
+		t.Fatalf("Divide returns %v, want a2 and a10", results)
+	}
+}
@@ -0,0 +1,378 @@
+package mrsoft
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
+
+// passPoint represents a pass in move lists of engines and the history
+var passPoint = Point{0, 0}
+
+// directions lists all 8 directions around a cell
+var directions = [8]direction{
+	{-1, -1}, {0, -1}, {1, -1},
+	{-1, 0}, {1, 0},
+	{-1, 1}, {0, 1}, {1, 1},
+}
+
+// Position represents a board status and the Player to move.
+// Engines search on Position so that the Game and the middleware are untouched.
+type Position struct {
+	b     board
+	crr   Player
+	rules RuleSet
+	// Zobrist hash of the stones
+	hash uint64
+}
+
+// Position returns a copy of the current position of the Game
+func (g *Game) Position() Position {
+	return Position{b: g.b, crr: g.crr, rules: g.rules, hash: g.hash}
+}
+
+// InitialPosition returns the Position at the beginning of the Game on 8 x 8 board
+func InitialPosition() Position {
+	pos, _ := NewPosition(8)
+	return pos
+}
+
+// NewPosition returns the Position at the beginning of the Game on n x n board
+func NewPosition(n int) (Position, error) {
+	b, err := newBoard(n)
+
+	return Position{b: b, crr: BLACK, hash: b.zobrist()}, err
+}
+
+// Rules returns the RuleSet of the Position
+func (pos *Position) Rules() RuleSet {
+	return pos.rules
+}
+
+// Size returns the number of cells on a side of the board
+func (pos *Position) Size() int {
+	return pos.b.size()
+}
+
+// Turn returns the Player to move
+func (pos *Position) Turn() Player {
+	return pos.crr
+}
+
+// At returns the State of the cell at p
+func (pos *Position) At(p Point) State {
+	return pos.b[p[1]][p[0]]
+}
+
+// canFlip reports whether putting a stone of pl on p flips any stone in direction d
+func (b *board) canFlip(p Point, pl Player, d direction) bool {
+	x, y := p[0]+d[0], p[1]+d[1]
+
+	if b[y][x] != pl.enemy().color() {
+		return false
+	}
+
+	for {
+		x, y = x+d[0], y+d[1]
+
+		switch b[y][x] {
+		case pl.color():
+			return true
+		case pl.enemy().color():
+			continue
+		default:
+			return false
+		}
+	}
+}
+
+// canPut reports whether pl can put a stone on p
+func (b *board) canPut(p Point, pl Player) bool {
+	if b[p[1]][p[0]] != NONE {
+		return false
+	}
+
+	for _, d := range directions {
+		if b.canFlip(p, pl, d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Moves returns the available points of the Player to move
+func (pos *Position) Moves() []Point {
+	moves := []Point{}
+
+	n := pos.b.size()
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			p := Point{x, y}
+			if pos.b.canPut(p, pos.crr) {
+				moves = append(moves, p)
+			}
+		}
+	}
+
+	return moves
+}
+
+// hasMoves reports whether pl has any available point
+func (pos *Position) hasMoves(pl Player) bool {
+	n := pos.b.size()
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			if pos.b.canPut(Point{x, y}, pl) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// play puts a stone of the Player to move on p, passes the turn
+// and returns the flipped points. p must be available.
+func (pos *Position) play(p Point) []Point {
+	return pos.playAppend(p, nil)
+}
+
+// playAppend is the same as play but appends the flipped points to flips
+func (pos *Position) playAppend(p Point, flips []Point) []Point {
+	pos.b[p[1]][p[0]] = pos.crr.color()
+	pos.hash ^= zobristStone(p, pos.crr.color())
+
+	for _, d := range directions {
+		if !pos.b.canFlip(p, pos.crr, d) {
+			continue
+		}
+
+		for q := (Point{p[0] + d[0], p[1] + d[1]}); pos.At(q) == pos.crr.enemy().color(); q = (Point{q[0] + d[0], q[1] + d[1]}) {
+			pos.b.flip(q)
+			pos.hash ^= zobristFlip(q)
+			flips = append(flips, q)
+		}
+	}
+
+	pos.crr = pos.crr.enemy()
+
+	return flips
+}
+
+// unplay reverts play(p) which returned flips
+func (pos *Position) unplay(p Point, flips []Point) {
+	pos.crr = pos.crr.enemy()
+
+	for _, q := range flips {
+		pos.b.flip(q)
+		pos.hash ^= zobristFlip(q)
+	}
+
+	pos.hash ^= zobristStone(p, pos.crr.color())
+	pos.b[p[1]][p[0]] = NONE
+}
+
+// pass passes the turn to the enemy
+func (pos *Position) pass() {
+	pos.crr = pos.crr.enemy()
+}
+
+// move plays m or passes when m is passPoint
+func (pos *Position) move(m Point) {
+	if m.equal(passPoint) {
+		pos.pass()
+		return
+	}
+
+	pos.play(m)
+}
+
+// Replay plays moves in order, passing the turn when the Player to move has no available point.
+// passPoint is allowed only for such a Player.
+func (pos *Position) Replay(moves []Point) error {
+	for i, m := range moves {
+		if m.equal(passPoint) {
+			if pos.hasMoves(pos.crr) {
+				return fmt.Errorf("%s can't pass at move %d", pos.crr, i+1)
+			}
+
+			pos.pass()
+			continue
+		}
+
+		if !pos.hasMoves(pos.crr) {
+			pos.pass()
+		}
+
+		if !pos.b.contains(m) || !pos.b.canPut(m, pos.crr) {
+			return fmt.Errorf("%s is not available for %s at move %d", m.Notation(), pos.crr, i+1)
+		}
+
+		pos.play(m)
+	}
+
+	return nil
+}
+
+// IsFinish reports whether neither Player has available points
+func (pos *Position) IsFinish() bool {
+	return !pos.hasMoves(pos.crr) && !pos.hasMoves(pos.crr.enemy())
+}
+
+// Count returns the numbers of black stones, white stones and blank cells
+func (pos *Position) Count() (black, white, blank int) {
+	n := pos.b.size()
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			switch pos.b[y][x] {
+			case BLACK:
+				black++
+			case WHITE:
+				white++
+			case NONE:
+				blank++
+			}
+		}
+	}
+
+	return
+}
+
+// Winner returns the Player who has more stones, or fewer stones by ANTI rules,
+// or NONE on draw
+func (pos *Position) Winner() Player {
+	black, white, _ := pos.Count()
+	diff := (black - white) * pos.rules.sign()
+
+	switch {
+	case diff > 0:
+		return BLACK
+	case diff < 0:
+		return WHITE
+	default:
+		return NONE
+	}
+}
+
+// score returns the disc differential for pl at the end of the game,
+// which is negated by ANTI rules so that the larger is always better
+func (pos *Position) score(pl Player) int {
+	black, white, _ := pos.Count()
+	diff := (black - white) * pos.rules.sign()
+
+	if pl == WHITE {
+		return -diff
+	}
+
+	return diff
+}
+
+// maxScore returns a bound larger than any disc differential of pos
+func (pos *Position) maxScore() int {
+	n := pos.b.size()
+
+	return n*n + 1
+}
+
+// positionKey identifies a Position by bit sets of stones
+type positionKey struct {
+	black, white [2]uint64
+	crr          Player
+	rules        RuleSet
+	n            int
+}
+
+// less reports whether k orders before l
+func (k positionKey) less(l positionKey) bool {
+	for i := range k.black {
+		if k.black[i] != l.black[i] {
+			return k.black[i] < l.black[i]
+		}
+	}
+
+	for i := range k.white {
+		if k.white[i] != l.white[i] {
+			return k.white[i] < l.white[i]
+		}
+	}
+
+	return false
+}
+
+// key returns the positionKey of pos
+func (pos *Position) key() positionKey {
+	return pos.transformedKey(0)
+}
+
+// transformedKey returns the positionKey of pos transformed by the symmetry k
+func (pos *Position) transformedKey(k int) (key positionKey) {
+	key.crr = pos.crr
+	key.rules = pos.rules
+	key.n = pos.b.size()
+
+	for y := 1; y <= key.n; y++ {
+		for x := 1; x <= key.n; x++ {
+			q := Point{x, y}.transform(k, key.n)
+			i := uint((q[1]-1)*key.n + q[0] - 1)
+
+			switch pos.b[y][x] {
+			case BLACK:
+				key.black[i/64] |= 1 << (i % 64)
+			case WHITE:
+				key.white[i/64] |= 1 << (i % 64)
+			}
+		}
+	}
+
+	return
+}
+
+// canonical returns the minimum positionKey among all 8 symmetries of pos
+func (pos *Position) canonical() (key positionKey) {
+	key = pos.key()
+
+	for k := 1; k < 8; k++ {
+		if t := pos.transformedKey(k); t.less(key) {
+			key = t
+		}
+	}
+
+	return
+}
+
+// Hash returns the hash of pos, which is the same for all 8 symmetries of pos
+func (pos *Position) Hash() uint64 {
+	k := pos.canonical()
+	h := fnv.New64a()
+
+	binary.Write(h, binary.LittleEndian, []uint64{
+		k.black[0], k.black[1], k.white[0], k.white[1],
+		uint64(k.crr), uint64(k.rules), uint64(k.n),
+	})
+
+	return h.Sum64()
+}
+
+// transform maps p by the symmetry k of n x n board.
+// 0-3 are identity and mirrors, 4-7 are them combined with transposition.
+func (p Point) transform(k, n int) Point {
+	x, y := p[0], p[1]
+
+	if k >= 4 {
+		x, y = y, x
+	}
+
+	if k&1 != 0 {
+		x = n + 1 - x
+	}
+
+	if k&2 != 0 {
+		y = n + 1 - y
+	}
+
+	return Point{x, y}
+}
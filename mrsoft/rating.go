@@ -0,0 +1,36 @@
+package mrsoft
+
+import "fmt"
+
+// Rater is told the result of the finished Game and rates the players.
+// A Rater which is also an Observer is told the Events of the Game as well.
+type Rater interface {
+	// Rate records r and returns the ratings to show in the summary
+	Rate(r *Record) (string, error)
+}
+
+// SetRater sets the Rater called when the Game finishes. nil disables it.
+func (g *Game) SetRater(r Rater) {
+	g.rater = r
+}
+
+// rate records the finished Game by the Rater, whose error doesn't fail the Game
+func (g *Game) rate() {
+	if g.rater == nil {
+		return
+	}
+
+	g.ratings, g.rateError = g.rater.Rate(g.Record())
+}
+
+// printRatings prints the ratings given by rate
+func (g *Game) printRatings() {
+	if g.rateError != nil {
+		fmt.Printf("\nFAILED TO RATE: %s\n", g.rateError)
+		return
+	}
+
+	if g.ratings != "" {
+		fmt.Printf("\n# RATING\n%s\n", g.ratings)
+	}
+}
@@ -0,0 +1,132 @@
+package mrsoft
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type recordingRater struct {
+	records []*Record
+	err     error
+}
+
+func (r *recordingRater) Rate(rec *Record) (string, error) {
+	r.records = append(r.records, rec)
+	return "alice\t1516 (+16)", r.err
+}
+
+func TestRater(t *testing.T) {
+	for _, err := range []error{nil, errors.New("disk full")} {
+		g, e := NewGame(&placingMiddleware{}, Size(4))
+
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		g.SetEngine(BLACK, firstEngine{})
+		g.SetEngine(WHITE, firstEngine{})
+
+		r := &recordingRater{err: err}
+		g.SetRater(r)
+
+		// the error of the Rater doesn't fail the Game
+		if e = g.Start(); e != nil {
+			t.Fatal(e)
+		}
+
+		if len(r.records) != 1 {
+			t.Fatalf("Rate is called %d times", len(r.records))
+		}
+
+		if len(r.records[0].Moves) == 0 || r.records[0].Black+r.records[0].White == 0 {
+			t.Fatalf("Rate is called with %+v", r.records[0])
+		}
+
+		// printing the summary again doesn't store the Game twice
+		g.printSummary()
+
+		if len(r.records) != 1 {
+			t.Fatalf("Rate is called %d times by printing the summary", len(r.records))
+		}
+	}
+}
+
+func TestRaterRemote(t *testing.T) {
+	g, err := NewGame(&placingMiddleware{}, Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &recordingRater{}
+	g.SetRater(r)
+
+	// the remote players play the first available moves
+	for pos := g.Position(); !pos.IsFinish(); pos = g.Position() {
+		p := passPoint
+
+		if moves := pos.Moves(); len(moves) != 0 {
+			p = moves[0]
+		}
+
+		if err = g.Play(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(r.records) != 1 {
+		t.Fatalf("Rate is called %d times", len(r.records))
+	}
+
+	if len(r.records[0].Moves) == 0 {
+		t.Fatalf("Rate is called with %+v", r.records[0])
+	}
+}
+
+// observingRater is a Rater which observes the starts of the Games
+type observingRater struct {
+	recordingRater
+	starts []time.Time
+}
+
+func (r *observingRater) Observe(e Event) {
+	if e.Kind == StartEvent {
+		r.starts = append(r.starts, e.Time)
+	}
+}
+
+func TestRaterObserver(t *testing.T) {
+	g, err := NewGame(&placingMiddleware{}, Size(4))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g.SetEngine(BLACK, firstEngine{})
+	g.SetEngine(WHITE, firstEngine{})
+
+	r := &observingRater{}
+	g.SetRater(r)
+
+	// each Game tells its start
+	for i := 1; i <= 2; i++ {
+		if i > 1 {
+			if err = g.Restart(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err = g.Start(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(r.starts) != i || len(r.records) != i {
+			t.Fatalf("%d starts and %d records after %d games", len(r.starts), len(r.records), i)
+		}
+	}
+
+	if !r.starts[1].After(r.starts[0]) {
+		t.Fatalf("the second Game started at %s, before the first at %s", r.starts[1], r.starts[0])
+	}
+}
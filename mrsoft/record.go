@@ -0,0 +1,328 @@
+package mrsoft
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Record is the record of a Game which can be saved and replayed
+type Record struct {
+	Size  int     `json:"size"`
+	Rules RuleSet `json:"rules"`
+	// Start is the board diagram of the start, empty for the normal beginning
+	Start string  `json:"start,omitempty"`
+	Moves []Point `json:"moves"`
+	// Opening is the name of the opening, empty if unknown
+	Opening string `json:"opening,omitempty"`
+	// Variations are the first moves of the variation tree, nil without branches
+	Variations []*Variation `json:"variations,omitempty"`
+	// Times are the clocks of the moves, nil if not timed
+	Times []MoveTime `json:"times,omitempty"`
+	// TimeLoss is the Player who lost on time
+	TimeLoss Player `json:"time_loss,omitempty"`
+	// Black and White are the numbers of stones at the last position
+	Black int `json:"black"`
+	White int `json:"white"`
+	// IllegalBlack and IllegalWhite are the numbers of illegal moves tried
+	IllegalBlack int `json:"illegal_black,omitempty"`
+	IllegalWhite int `json:"illegal_white,omitempty"`
+	// Analysis is the result of the post-game analysis, nil if not analyzed
+	Analysis *Analysis `json:"analysis,omitempty"`
+}
+
+// Record returns the record of the Game
+func (g *Game) Record() *Record {
+	pos := g.Position()
+	black, white, _ := pos.Count()
+
+	start := ""
+
+	if !g.start.isInitial() {
+		start = g.start.Diagram()
+	}
+
+	var variations []*Variation
+
+	if g.tree.branched() {
+		variations = g.tree.Children
+	}
+
+	var times []MoveTime
+
+	if g.clock != nil {
+		for _, r := range g.history {
+			times = append(times, r.time)
+		}
+	}
+
+	return &Record{
+		Size:         g.b.size(),
+		Rules:        g.rules,
+		Start:        start,
+		Moves:        g.moves(),
+		Opening:      g.opening,
+		Variations:   variations,
+		Times:        times,
+		TimeLoss:     g.timeout,
+		Black:        black,
+		White:        white,
+		IllegalBlack: g.illegals[BLACK],
+		IllegalWhite: g.illegals[WHITE],
+		Analysis:     g.analysis,
+	}
+}
+
+// Winner returns the winner by the numbers of stones and the rules, NONE on draw.
+// The Player who lost on time always loses.
+func (r *Record) Winner() Player {
+	if r.TimeLoss != NONE {
+		return r.TimeLoss.enemy()
+	}
+
+	diff := (r.Black - r.White) * r.Rules.sign()
+
+	switch {
+	case diff > 0:
+		return BLACK
+	case diff < 0:
+		return WHITE
+	default:
+		return NONE
+	}
+}
+
+// Transcript returns the rules, the board size and the moves such as "standard 8 f5d6c3".
+// The board diagram of the start is put before the moves unless the Game starts from the normal beginning.
+func (r *Record) Transcript() string {
+	if r.Start != "" {
+		return fmt.Sprintf("%s %d %s %s", r.Rules, r.Size, r.Start, FormatMoves(r.Moves))
+	}
+
+	return fmt.Sprintf("%s %d %s", r.Rules, r.Size, FormatMoves(r.Moves))
+}
+
+// ParseTranscript replays the transcript and returns its Record
+func ParseTranscript(s string) (*Record, error) {
+	fields := strings.Fields(s)
+
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("Invalid transcript: %q", s)
+	}
+
+	r := &Record{}
+
+	rules, err := ParseRuleSet(fields[0])
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.Rules = rules
+
+	if r.Size, err = strconv.Atoi(fields[1]); err != nil {
+		return nil, fmt.Errorf("Invalid board size: %q", fields[1])
+	}
+
+	moves := fields[2:]
+
+	if len(moves) != 0 {
+		if _, err := ParseBoard(moves[0]); err == nil {
+			r.Start, moves = moves[0], moves[1:]
+		}
+	}
+
+	if r.Moves, err = ParseMoves(strings.Join(moves, "")); err != nil {
+		return nil, err
+	}
+
+	return r, r.replay()
+}
+
+// replay validates the moves and the variations and counts the stones at the last position
+func (r *Record) replay() error {
+	pos, err := r.Position()
+
+	if err != nil {
+		return err
+	}
+
+	if r.Variations != nil {
+		for _, l := range r.Lines() {
+			v, _ := r.StartPosition()
+
+			if err = v.Replay(l); err != nil {
+				return fmt.Errorf("Invalid variation %s: %s", FormatMoves(l), err)
+			}
+		}
+	}
+
+	r.Black, r.White, _ = pos.Count()
+
+	return nil
+}
+
+// StartPosition returns the Position at the start of the Game
+func (r *Record) StartPosition() (pos Position, err error) {
+	if r.Start == "" {
+		pos, err = NewPosition(r.Size)
+	} else {
+		pos, err = ParseBoard(r.Start)
+	}
+
+	if err != nil {
+		return
+	}
+
+	if pos.Size() != r.Size {
+		return pos, fmt.Errorf("Start is %dx%d board, not %dx%d", pos.Size(), pos.Size(), r.Size, r.Size)
+	}
+
+	pos.rules = r.Rules
+
+	return
+}
+
+// Position returns the Position after the moves
+func (r *Record) Position() (Position, error) {
+	pos, err := r.StartPosition()
+
+	if err != nil {
+		return pos, err
+	}
+
+	return pos, pos.Replay(r.Moves)
+}
+
+// Lines returns all lines of the variation tree, or only the moves without branches
+func (r *Record) Lines() [][]Point {
+	if r.Variations == nil {
+		return [][]Point{r.Moves}
+	}
+
+	root := &Variation{Children: r.Variations}
+
+	return root.Lines()
+}
+
+// Positions returns the Positions from the start to the last move
+func (r *Record) Positions() ([]Position, error) {
+	pos, err := r.StartPosition()
+
+	if err != nil {
+		return nil, err
+	}
+
+	positions := []Position{pos}
+
+	for i := range r.Moves {
+		if err = pos.Replay(r.Moves[i : i+1]); err != nil {
+			return nil, fmt.Errorf("%s at move %d", err, i+1)
+		}
+
+		positions = append(positions, pos)
+	}
+
+	return positions, nil
+}
+
+// Resume continues the Game of the Record from its last position.
+// Give Timed before Resume to restore the clock.
+func Resume(r *Record) Option {
+	return func(g *Game) (err error) {
+		if _, err = r.Position(); err != nil {
+			return
+		}
+
+		start, _ := r.StartPosition()
+
+		g.b, g.crr, g.rules, g.start = start.b, start.crr, start.rules, start
+
+		for _, l := range r.Lines() {
+			n := g.tree
+
+			for _, p := range l {
+				n = n.child(p)
+			}
+		}
+
+		// indexes of the passes in the history without the clock in the Record
+		untimed := map[int]bool{}
+
+		for i, p := range r.Moves {
+			g.setAvailable()
+
+			// the Record may omit passes
+			if len(g.available) == 0 && !p.equal(passPoint) {
+				untimed[len(g.history)] = true
+				g.pass()
+				g.setAvailable()
+			}
+
+			if p.equal(passPoint) {
+				if i < len(r.Times) {
+					g.pass()
+					g.history[len(g.history)-1].time = r.Times[i]
+				} else {
+					untimed[len(g.history)] = true
+					g.pass()
+				}
+
+				continue
+			}
+
+			pr := PutRecord{point: p, player: g.crr, flips: g.flips(p)}
+
+			if i < len(r.Times) {
+				pr.time = r.Times[i]
+			}
+
+			g.b.put(p, g.crr.color())
+
+			for _, q := range pr.flips {
+				g.b.flip(q)
+			}
+
+			g.history = append(g.history, pr)
+			g.node = g.node.child(p)
+			g.crr = g.crr.enemy()
+		}
+
+		if g.clock != nil && len(r.Times) != 0 {
+			for i := range g.history {
+				pr := &g.history[i]
+				pr.before = g.clock.left[pr.player]
+
+				// passing takes no time
+				if untimed[i] {
+					pr.time.Left = pr.before
+				}
+
+				g.clock.left[pr.player] = pr.time.Left
+			}
+		}
+
+		return nil
+	}
+}
+
+// Write saves the Record as JSON
+func (r *Record) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// ReadRecord loads the Record saved by Write and validates its moves
+func ReadRecord(rd io.Reader) (*Record, error) {
+	r := &Record{}
+
+	if err := json.NewDecoder(rd).Decode(r); err != nil {
+		return nil, err
+	}
+
+	return r, r.replay()
+}
@@ -0,0 +1,161 @@
+package mrsoft
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrFinished is returned when a move is asked after the end of the Game
+var ErrFinished = errors.New("The game is over")
+
+// Load replaces the Game with the one of the Record, then changes the stones
+// on the board from the current position. Engines, the book, the analyzer,
+// the reviewer, the rater, the logger and the observer are kept.
+func (g *Game) Load(r *Record) error {
+	ng, err := NewGame(g.m, Size(r.Size), Resume(r))
+
+	if err != nil {
+		return err
+	}
+
+	from, to := g.Position(), ng.Position()
+
+	g.replace(ng)
+	g.notify(Event{Kind: StartEvent, Player: g.crr})
+
+	return g.arrange(&from, &to)
+}
+
+// Restart abandons the Game and starts a new one from the same starting position
+// with the same rules and time control. The stones on the board are changed
+// to the normal beginning, from which Start sets up the starting position.
+func (g *Game) Restart() error {
+	opts := []Option{Size(g.b.size()), Rules(g.rules), StartFrom(g.start)}
+
+	if g.clock != nil {
+		opts = append(opts, Timed(g.clock.tc))
+	}
+
+	ng, err := NewGame(g.m, opts...)
+
+	if err != nil {
+		return err
+	}
+
+	from := g.Position()
+	to, _ := NewPosition(g.b.size())
+
+	g.replace(ng)
+
+	return g.arrange(&from, &to)
+}
+
+// replace replaces the Game with ng, keeping engines, the book, the analyzer,
+// the reviewer, the rater, the logger and the observer
+func (g *Game) replace(ng *Game) {
+	ng.engines, ng.book, ng.analyzer, ng.reviewer, ng.rater = g.engines, g.book, g.analyzer, g.reviewer, g.rater
+	ng.log, ng.observer = g.log, g.observer
+
+	*g = *ng
+	g.setAvailable()
+}
+
+// Play plays p for the Player to move on behalf of a remote player.
+// The stone is put by the middleware or by hand as guided, then checked by the sensors.
+// The Game is reviewed, rated and summarized as in Start when it finishes.
+// p is passPoint to pass.
+func (g *Game) Play(p Point) (err error) {
+	g.setAvailable()
+
+	if p.equal(passPoint) {
+		if len(g.available) != 0 {
+			return fmt.Errorf("%s can't pass", g.crr)
+		}
+
+		g.passTurn()
+		return
+	}
+
+	if reason := g.illegalReason(p); reason != "" {
+		return fmt.Errorf("Illegal move %s: %s", p.Notation(), reason)
+	}
+
+	if err = g.place(p, g.crr.color()); err != nil {
+		return
+	}
+
+	if err = g.put(p); err != nil {
+		return
+	}
+
+	g.crr = g.crr.enemy()
+	g.setAvailable()
+
+	pos := g.Position()
+
+	if err = g.verify(&pos); err != nil {
+		return
+	}
+
+	if g.isFinish() {
+		return g.finish()
+	}
+
+	return
+}
+
+// Wait waits until the Player to move puts a stone on the board and plays it.
+// Illegal moves are rejected as in Start. passPoint is returned when the Player has to pass.
+// The Game is reviewed, rated and summarized as in Start when it finishes.
+func (g *Game) Wait() (p Point, err error) {
+	g.setAvailable()
+
+	if g.isFinish() {
+		return p, ErrFinished
+	}
+
+	if len(g.available) == 0 {
+		g.passTurn()
+		return passPoint, nil
+	}
+
+	for {
+		if p, err = g.getInput(); err != nil {
+			return
+		}
+
+		// commands typed on the terminal are for local games
+		if p[0] < 0 {
+			continue
+		}
+
+		if reason := g.illegalReason(p); reason != "" {
+			g.illegals[g.crr]++
+
+			if err = g.reject(p, reason); err != nil {
+				return
+			}
+
+			continue
+		}
+
+		if err = g.put(p); err != nil {
+			fmt.Println(err)
+
+			if err = g.waitRemoved(p); err != nil {
+				return
+			}
+
+			continue
+		}
+
+		g.crr = g.crr.enemy()
+		g.setAvailable()
+
+		if g.isFinish() {
+			err = g.finish()
+		}
+
+		return p, err
+	}
+}
@@ -0,0 +1,71 @@
+package mrsoft
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleSet represents rules which decide the winner of the Game
+type RuleSet int
+
+const (
+	// STANDARD is the normal rules where the Player who has more stones wins
+	STANDARD RuleSet = iota
+	// ANTI is anti-reversi where the Player who has fewer stones wins
+	ANTI
+)
+
+func (r RuleSet) String() string {
+	switch r {
+	case STANDARD:
+		return "standard"
+	case ANTI:
+		return "anti"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseRuleSet parses "standard" or "anti"
+func ParseRuleSet(s string) (RuleSet, error) {
+	switch strings.ToLower(s) {
+	case "standard":
+		return STANDARD, nil
+	case "anti":
+		return ANTI, nil
+	default:
+		return STANDARD, fmt.Errorf("Unknown rules: %q", s)
+	}
+}
+
+// MarshalText encodes r as its name
+func (r RuleSet) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes r from its name
+func (r *RuleSet) UnmarshalText(text []byte) (err error) {
+	*r, err = ParseRuleSet(string(text))
+	return
+}
+
+// sign returns 1 when more stones are better, -1 when fewer stones are better
+func (r RuleSet) sign() int {
+	if r == ANTI {
+		return -1
+	}
+
+	return 1
+}
+
+// Rules makes the Game played by the RuleSet
+func Rules(r RuleSet) Option {
+	return func(g *Game) error {
+		if r != STANDARD && r != ANTI {
+			return fmt.Errorf("Unknown rules: %d", r)
+		}
+
+		g.rules = r
+		return nil
+	}
+}
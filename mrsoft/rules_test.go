@@ -0,0 +1,94 @@
+package mrsoft
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestAntiGame(t *testing.T) {
+	g, err := NewGame(&placingMiddleware{}, Rules(ANTI))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []Player{BLACK, WHITE} {
+		s := NewSearcher()
+		s.Depth = 1
+		g.SetEngine(p, s)
+	}
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := g.Record()
+
+	if r.Rules != ANTI {
+		t.Fatalf("Record has %s rules", r.Rules)
+	}
+
+	switch w := r.Winner(); {
+	case r.Black < r.White && w != BLACK, r.White < r.Black && w != WHITE, r.Black == r.White && w != NONE:
+		t.Fatalf("%s wins with %d black and %d white stones by anti rules", w, r.Black, r.White)
+	}
+
+	pos := g.Position()
+	if pos.Winner() != r.Winner() {
+		t.Fatalf("Position says %s wins, but Record says %s", pos.Winner(), r.Winner())
+	}
+}
+
+func TestAntiSolver(t *testing.T) {
+	s := NewSolver()
+
+	for seed := int64(1); seed <= 5; seed++ {
+		pos := randomPosition(seed, 9)
+		pos.rules = ANTI
+
+		if got, want := s.Solve(pos), minimax(pos, false); got != want {
+			t.Fatalf("seed %d: Solve returned %d, want %d", seed, got, want)
+		}
+	}
+}
+
+func TestRecordTranscript(t *testing.T) {
+	r, err := ParseTranscript("anti 8 f5d6c3d3c4")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Rules != ANTI || r.Size != 8 || len(r.Moves) != 5 || r.Black+r.White != 9 {
+		t.Fatalf("ParseTranscript returned %+v", r)
+	}
+
+	if s := r.Transcript(); s != "anti 8 f5d6c3d3c4" {
+		t.Fatalf("Transcript returned %q", s)
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err = r.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), `"rules": "anti"`) {
+		t.Fatalf("saved Record doesn't have the rules: %s", buf)
+	}
+
+	read, err := ReadRecord(buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if read.Transcript() != r.Transcript() || read.Black != r.Black {
+		t.Fatalf("ReadRecord returned %+v", read)
+	}
+
+	if _, err = ParseTranscript("standard 8 f5f5"); err == nil {
+		t.Fatal("ParseTranscript accepted unavailable move")
+	}
+}
@@ -0,0 +1,257 @@
+package mrsoft
+
+import (
+	"errors"
+	"sort"
+)
+
+// DefaultWeights is the value of each cell of 8 x 8 board used by NewSearcher
+var DefaultWeights = [8][8]int{
+	{120, -20, 20, 5, 5, 20, -20, 120},
+	{-20, -40, -5, -5, -5, -5, -40, -20},
+	{20, -5, 15, 3, 3, 15, -5, 20},
+	{5, -5, 3, 3, 3, 3, -5, 5},
+	{5, -5, 3, 3, 3, 3, -5, 5},
+	{20, -5, 15, 3, 3, 15, -5, 20},
+	{-20, -40, -5, -5, -5, -5, -40, -20},
+	{120, -20, 20, 5, 5, 20, -20, 120},
+}
+
+// winScore is added to the disc differential of finished games
+// so that a won game is better than any evaluation
+const winScore = 10000
+
+// pointsPerDisc is the rough value of a disc in the evaluation of the Searcher
+const pointsPerDisc = 10
+
+// Discs returns the score as a disc differential, estimated for the points of the Searcher.
+// ok is false for the win rate which can't be told in discs.
+func (s MoveScore) Discs() (discs float64, ok bool) {
+	switch {
+	case s.Unit == DISCS:
+		return float64(s.Score), true
+	case s.Unit != POINTS:
+		return 0, false
+	// the end of the game is found in the search
+	case s.Score >= winScore:
+		return float64(s.Score - winScore), true
+	case s.Score <= -winScore:
+		return float64(s.Score + winScore), true
+	default:
+		return float64(s.Score) / pointsPerDisc, true
+	}
+}
+
+// Searcher is an Engine using alpha-beta search with an evaluation function
+// which sums weights of cells and mobility
+type Searcher struct {
+	// Depth is the search depth in plies
+	Depth int
+	// Weights is the value of each cell of 8 x 8 board indexed by [y-1][x-1].
+	// Cells of other sizes are valued by their kinds.
+	Weights [8][8]int
+	// Mobility is the value of one available point
+	Mobility int
+	// Table is the transposition table, nil for none.
+	// It may be shared with Searchers of the same settings running concurrently.
+	Table *TranspositionTable
+}
+
+// NewSearcher returns a Searcher with default settings
+func NewSearcher() *Searcher {
+	return &Searcher{
+		Depth:    4,
+		Weights:  DefaultWeights,
+		Mobility: 5,
+	}
+}
+
+// Move returns the move with the best evaluation
+func (s *Searcher) Move(pos Position) (Point, error) {
+	moves := pos.Moves()
+
+	if len(moves) == 0 {
+		return Point{}, errors.New("There is no available point")
+	}
+
+	if s.Table != nil {
+		s.Table.NewSearch()
+	}
+
+	best, alpha := moves[0], -2*winScore
+
+	for _, m := range s.order(moves, pos.b.size()) {
+		flips := pos.play(m)
+		v := -s.search(&pos, s.Depth-1, -2*winScore, -alpha, false)
+		pos.unplay(m, flips)
+
+		if v > alpha {
+			best, alpha = m, v
+		}
+	}
+
+	return best, nil
+}
+
+// Analyze returns the evaluation of each available move
+// from the view of the Player to move, in descending order of score
+func (s *Searcher) Analyze(pos Position) ([]MoveScore, error) {
+	moves := pos.Moves()
+
+	if len(moves) == 0 {
+		return nil, errors.New("There is no available point")
+	}
+
+	scores := make([]MoveScore, len(moves))
+
+	for i, m := range moves {
+		flips := pos.play(m)
+		scores[i] = MoveScore{Move: m, Score: -s.search(&pos, s.Depth-1, -2*winScore, 2*winScore, false), Unit: POINTS}
+		pos.unplay(m, flips)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Score > scores[j].Score
+	})
+
+	return scores, nil
+}
+
+// search is a negamax search with alpha-beta pruning.
+// passed reports whether the last move was a pass.
+func (s *Searcher) search(pos *Position, depth, alpha, beta int, passed bool) int {
+	moves := pos.Moves()
+
+	if len(moves) == 0 {
+		if passed {
+			return s.final(pos)
+		}
+
+		pos.pass()
+		v := -s.search(pos, depth, -beta, -alpha, true)
+		pos.pass()
+
+		return v
+	}
+
+	if depth <= 0 {
+		return s.evaluate(pos, len(moves))
+	}
+
+	moves = s.order(moves, pos.b.size())
+	window := alpha
+
+	if s.Table != nil {
+		if e, ok := s.Table.Probe(pos.Zobrist()); ok {
+			if e.Depth >= depth && e.cutoff(alpha, beta) {
+				return e.Score
+			}
+
+			// try the best move of the last search first
+			for i, m := range moves {
+				if m.equal(e.Best) {
+					copy(moves[1:i+1], moves[:i])
+					moves[0] = m
+					break
+				}
+			}
+		}
+	}
+
+	best, bestMove := -2*winScore, moves[0]
+
+	for _, m := range moves {
+		flips := pos.play(m)
+		v := -s.search(pos, depth-1, -beta, -alpha, false)
+		pos.unplay(m, flips)
+
+		if v > best {
+			best, bestMove = v, m
+		}
+
+		if v > alpha {
+			alpha = v
+		}
+
+		if alpha >= beta {
+			break
+		}
+	}
+
+	if s.Table != nil {
+		s.Table.Store(TTEntry{Key: pos.Zobrist(), Score: best, Depth: depth, Bound: boundOf(best, window, beta), Best: bestMove})
+	}
+
+	return best
+}
+
+// order sorts moves in descending order of weights
+func (s *Searcher) order(moves []Point, n int) []Point {
+	sort.SliceStable(moves, func(i, j int) bool {
+		return s.weight(moves[i], n) > s.weight(moves[j], n)
+	})
+
+	return moves
+}
+
+// weight returns the value of p on n x n board
+func (s *Searcher) weight(p Point, n int) int {
+	if n == 8 {
+		return s.Weights[p[1]-1][p[0]-1]
+	}
+
+	edge := func(v int) bool { return v == 1 || v == n }
+	second := func(v int) bool { return v == 2 || v == n-1 }
+
+	switch {
+	case isCorner(p, n):
+		return 120
+	case isXSquare(p, n):
+		return -40
+	case edge(p[0]) && second(p[1]) || second(p[0]) && edge(p[1]):
+		return -20
+	case edge(p[0]) || edge(p[1]):
+		return 10
+	case second(p[0]) || second(p[1]):
+		return -5
+	default:
+		return 3
+	}
+}
+
+// final returns the score of the finished game for the Player to move
+func (s *Searcher) final(pos *Position) int {
+	diff := pos.score(pos.crr)
+
+	switch {
+	case diff > 0:
+		return winScore + diff
+	case diff < 0:
+		return -winScore + diff
+	default:
+		return 0
+	}
+}
+
+// evaluate returns the evaluation for the Player to move who has n available points
+func (s *Searcher) evaluate(pos *Position, n int) (v int) {
+	size := pos.b.size()
+
+	for y := 1; y <= size; y++ {
+		for x := 1; x <= size; x++ {
+			switch pos.b[y][x] {
+			case pos.crr.color():
+				v += s.weight(Point{x, y}, size)
+			case pos.crr.enemy().color():
+				v -= s.weight(Point{x, y}, size)
+			}
+		}
+	}
+
+	pos.pass()
+	enemy := len(pos.Moves())
+	pos.pass()
+
+	// stones are liabilities by ANTI rules
+	return v*pos.rules.sign() + s.Mobility*(n-enemy)
+}
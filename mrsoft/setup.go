@@ -0,0 +1,275 @@
+package mrsoft
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+)
+
+// ParseBoard parses a board diagram such as
+//
+//	--------
+//	--------
+//	---X----
+//	---XX---
+//	---XO---
+//	--------
+//	--------
+//	-------- O
+//
+// "X" or "*" is black, "O" is white and "-" or "." is empty.
+// Spaces are ignored and an optional last "X" or "O" is the Player to move, BLACK by default.
+func ParseBoard(s string) (pos Position, err error) {
+	s = strings.Join(strings.Fields(s), "")
+
+	n := int(math.Sqrt(float64(len(s))))
+
+	if pos.b, err = newBoard(n); err != nil {
+		return pos, fmt.Errorf("Invalid board diagram: %s", err)
+	}
+
+	pos.crr = BLACK
+
+	switch len(s) {
+	case n * n:
+	case n*n + 1:
+		pl, ok := map[byte]Player{'X': BLACK, '*': BLACK, 'O': WHITE}[s[n*n]]
+
+		if !ok {
+			return pos, fmt.Errorf("Invalid Player to move: %q", s[n*n])
+		}
+
+		pos.crr = pl
+	default:
+		return pos, fmt.Errorf("Invalid length of board diagram: %d", len(s))
+	}
+
+	for i := 0; i < n*n; i++ {
+		c, ok := map[byte]State{'X': BLACK, '*': BLACK, 'O': WHITE, '-': NONE, '.': NONE}[s[i]]
+
+		if !ok {
+			return pos, fmt.Errorf("Invalid cell %q in board diagram", s[i])
+		}
+
+		pos.b[i/n+1][i%n+1] = c
+	}
+
+	pos.hash = pos.b.zobrist()
+
+	return
+}
+
+// Diagram returns the board in one line in the format of ParseBoard
+func (pos *Position) Diagram() string {
+	n := pos.b.size()
+	s := make([]byte, 0, n*n+1)
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			s = append(s, map[State]byte{BLACK: 'X', WHITE: 'O', NONE: '-'}[pos.b[y][x]])
+		}
+	}
+
+	return string(append(s, map[Player]byte{BLACK: 'X', WHITE: 'O'}[pos.crr]))
+}
+
+// ParsePosition parses a board diagram of ParseBoard
+// or a transcript of Record.Transcript whose moves lead to the Position
+func ParsePosition(s string) (Position, error) {
+	pos, err := ParseBoard(s)
+
+	if err == nil {
+		return pos, nil
+	}
+
+	r, terr := ParseTranscript(s)
+
+	if terr != nil {
+		return Position{}, fmt.Errorf("Invalid position %q: %s", s, err)
+	}
+
+	return r.Position()
+}
+
+// isInitial reports whether pos is the beginning of a normal Game
+func (pos *Position) isInitial() bool {
+	initial, _ := NewPosition(pos.b.size())
+	initial.rules = pos.rules
+
+	return pos.key() == initial.key()
+}
+
+// StartFrom starts the Game from the stones and the Player to move of pos.
+// The rules are given by Rules.
+func StartFrom(pos Position) Option {
+	return func(g *Game) error {
+		g.b, g.crr = pos.b, pos.crr
+		return nil
+	}
+}
+
+// Turn makes pl move first
+func Turn(pl Player) Option {
+	return func(g *Game) error {
+		if pl != BLACK && pl != WHITE {
+			return fmt.Errorf("Invalid Player: %d", pl)
+		}
+
+		g.crr = pl
+		return nil
+	}
+}
+
+// Handicap gives corners to the weaker Player before the Game starts.
+// level is the number of corners from 1 to 4.
+func Handicap(level int, weaker Player) Option {
+	return func(g *Game) error {
+		n := g.b.size()
+		corners := []Point{{1, 1}, {n, n}, {n, 1}, {1, n}}
+
+		if level < 1 || len(corners) < level {
+			return fmt.Errorf("Handicap must be 1 to %d, not %d", len(corners), level)
+		}
+
+		for _, p := range corners[:level] {
+			if err := g.b.put(p, weaker.color()); err != nil {
+				return fmt.Errorf("Failed to put handicap on (%d, %d): %s", p[0], p[1], err)
+			}
+		}
+
+		return nil
+	}
+}
+
+// sensor is implemented by middlewares which can tell where stones are
+type sensor interface {
+	Sense() ([mrmiddle.SIZE][mrmiddle.SIZE]bool, error)
+}
+
+// setup guides the players from the normal beginning to the position to start or resume
+func (g *Game) setup() (err error) {
+	target := g.Position()
+
+	if target.isInitial() {
+		return
+	}
+
+	fmt.Println("# SETUP")
+
+	initial, _ := NewPosition(g.b.size())
+
+	if err = g.arrange(&initial, &target); err != nil {
+		return
+	}
+
+	fmt.Printf("%s STARTS\n", target.crr)
+
+	return
+}
+
+// arrange changes the stones on the board from the position from to the position to
+func (g *Game) arrange(from, to *Position) (err error) {
+	n := to.Size()
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			p := Point{x, y}
+			a, b := from.At(p), to.At(p)
+
+			switch {
+			case a == b:
+			case b == NONE:
+				fmt.Printf("REMOVE THE STONE ON (%d, %d)\n", x, y)
+			case a == NONE:
+				if err = g.place(p, b); err != nil {
+					return
+				}
+			default:
+				if err = g.m.Flip(x, y, b.pole()); err != nil {
+					return fmt.Errorf("Failed to flip: %s", err)
+				}
+			}
+		}
+	}
+
+	return g.verify(to)
+}
+
+// place puts a stone of c on p by the middleware or waits until it is put by hand
+func (g *Game) place(p Point, c State) error {
+	if pl, ok := g.m.(placer); ok {
+		return pl.Place(p[0], p[1], c.pole())
+	}
+
+	for {
+		fmt.Printf("PUT A %s STONE ON (%d, %d)\n", c, p[0], p[1])
+
+		x, y, err := g.m.GetInput()
+
+		if err != nil {
+			return fmt.Errorf("Failed to get input: %s", err)
+		}
+
+		if p.equal(Point{x, y}) {
+			return nil
+		}
+	}
+}
+
+// verify waits until the sensors find stones just on the cells of pos
+func (g *Game) verify(pos *Position) error {
+	s, ok := g.m.(sensor)
+
+	if !ok {
+		return nil
+	}
+
+	last := ""
+
+	for {
+		cells, err := s.Sense()
+
+		if err != nil {
+			return fmt.Errorf("Failed to sense: %s", err)
+		}
+
+		wrong := []Point{}
+
+		for y, row := range cells {
+			for x, v := range row {
+				p := Point{x + 1, y + 1}
+
+				if v != (pos.At(p) != NONE) {
+					wrong = append(wrong, p)
+				}
+			}
+		}
+
+		if len(wrong) == 0 {
+			return nil
+		}
+
+		// guide again only when the board changes
+		if fmt.Sprint(wrong) == last {
+			time.Sleep(mrmiddle.POLLTIME)
+			continue
+		}
+
+		last = fmt.Sprint(wrong)
+
+		g.log.WithField("cells", FormatMoves(wrong)).Warn("Stones on the board differ from the game")
+
+		for _, p := range wrong {
+			if pos.At(p) == NONE {
+				fmt.Printf("REMOVE THE STONE ON (%d, %d)\n", p[0], p[1])
+			} else {
+				fmt.Printf("PUT A %s STONE ON (%d, %d)\n", pos.At(p), p[0], p[1])
+			}
+		}
+
+		time.Sleep(mrmiddle.POLLTIME)
+	}
+}
@@ -0,0 +1,153 @@
+package mrsoft
+
+import (
+	"testing"
+
+	"github.com/69guitar1015/MagicReversi/mrmiddle"
+)
+
+// sensingMiddleware senses the stones put through the Game
+type sensingMiddleware struct {
+	placingMiddleware
+	cells [mrmiddle.SIZE][mrmiddle.SIZE]bool
+}
+
+func (m *sensingMiddleware) Place(x int, y int, pd mrmiddle.Pole) (err error) {
+	m.cells[y-1][x-1] = true
+
+	return m.placingMiddleware.Place(x, y, pd)
+}
+
+func (m *sensingMiddleware) Sense() ([mrmiddle.SIZE][mrmiddle.SIZE]bool, error) {
+	return m.cells, nil
+}
+
+func TestParseBoard(t *testing.T) {
+	pos, err := ParseBoard(`
+		--------
+		--------
+		---X----
+		---XX---
+		---XO---
+		--------
+		--------
+		-------- O`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := InitialPosition()
+	want.play(Point{4, 3})
+
+	if pos.key() != want.key() {
+		t.Fatalf("ParseBoard returns %s, want %s", pos.Diagram(), want.Diagram())
+	}
+
+	if again, err := ParseBoard(pos.Diagram()); err != nil || again.key() != pos.key() {
+		t.Fatalf("Diagram %s is not parsed back: %v", pos.Diagram(), err)
+	}
+
+	for _, s := range []string{"", "---", "----X---O---X---O-", "--------O------------------------------------------------------Z"} {
+		if _, err := ParseBoard(s); err == nil {
+			t.Fatalf("ParseBoard accepts %q", s)
+		}
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	pos, err := ParsePosition("standard 8 f5d6")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := InitialPosition()
+	want.Replay([]Point{{6, 5}, {4, 6}})
+
+	if pos.key() != want.key() {
+		t.Fatalf("ParsePosition returns %s, want %s", pos.Diagram(), want.Diagram())
+	}
+}
+
+func TestHandicap(t *testing.T) {
+	m := &sensingMiddleware{}
+
+	g, err := NewGame(m, Handicap(2, WHITE))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []Point{{1, 1}, {8, 8}} {
+		if g.b[p[1]][p[0]] != WHITE {
+			t.Fatalf("(%d, %d) is %s, want WHITE", p[0], p[1], g.b[p[1]][p[0]])
+		}
+	}
+
+	if _, err = NewGame(m, Handicap(5, WHITE)); err == nil {
+		t.Fatal("NewGame accepts handicap 5")
+	}
+
+	// the initial stones are already on the board
+	for _, p := range []Point{{4, 4}, {5, 4}, {4, 5}, {5, 5}} {
+		m.cells[p[1]-1][p[0]-1] = true
+	}
+
+	for _, p := range []Player{BLACK, WHITE} {
+		s := NewSearcher()
+		s.Depth = 1
+		g.SetEngine(p, s)
+	}
+
+	if err = g.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := g.Record()
+
+	if r.Start == "" {
+		t.Fatal("Record has no start")
+	}
+
+	replayed, err := ParseTranscript(r.Transcript())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if replayed.Black != r.Black || replayed.White != r.White {
+		t.Fatalf("transcript ends with %d-%d, want %d-%d", replayed.Black, replayed.White, r.Black, r.White)
+	}
+}
+
+func TestStartFrom(t *testing.T) {
+	pos, err := ParseBoard("X---" + "-XO-" + "-XX-" + "----" + "O")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g, err := NewGame(&dammyMiddleware{r: [][2]int{{2, 1}, {1, 1}}}, StartFrom(pos))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.b.size() != 4 || g.crr != WHITE || g.start.key() != pos.key() {
+		t.Fatalf("Game starts from %s, want %s", g.start.Diagram(), pos.Diagram())
+	}
+
+	// the Game waits until a stone is put on (1, 1)
+	if err = g.setup(); err != nil {
+		t.Fatal(err)
+	}
+
+	if g, err = NewGame(&dammyMiddleware{}, Turn(WHITE)); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.crr != WHITE {
+		t.Fatalf("Turn(WHITE) makes %s move first", g.crr)
+	}
+}
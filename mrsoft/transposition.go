@@ -0,0 +1,146 @@
+package mrsoft
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Bound is the type of the score stored in the TranspositionTable
+type Bound int8
+
+const (
+	// ExactBound is the exact score
+	ExactBound Bound = iota
+	// LowerBound means the score is at least the stored one
+	LowerBound
+	// UpperBound means the score is at most the stored one
+	UpperBound
+)
+
+// boundOf returns the Bound of the score v searched in the window (alpha, beta)
+func boundOf(v, alpha, beta int) Bound {
+	switch {
+	case v <= alpha:
+		return UpperBound
+	case v >= beta:
+		return LowerBound
+	default:
+		return ExactBound
+	}
+}
+
+// TTEntry is a search result of a position
+type TTEntry struct {
+	// Key is the Zobrist hash of the position
+	Key   uint64
+	Score int
+	// Depth is the remaining depth of the search
+	Depth int
+	Bound Bound
+	// Best is the best move found
+	Best Point
+}
+
+// cutoff reports whether the entry decides the score in the window (alpha, beta)
+func (e *TTEntry) cutoff(alpha, beta int) bool {
+	switch e.Bound {
+	case ExactBound:
+		return true
+	case LowerBound:
+		return e.Score >= beta
+	default:
+		return e.Score <= alpha
+	}
+}
+
+type ttSlot struct {
+	entry TTEntry
+	// generation of the search which stored the entry
+	generation uint32
+	used       bool
+}
+
+// ttLocks is the number of mutexes sharing the slots
+const ttLocks = 256
+
+// TranspositionTable is a fixed-size hash table of search results.
+// It is safe for concurrent use.
+//
+// An entry replaces the one in its slot when the slot has the same position,
+// is stored by an older search or is not deeper than the new one.
+type TranspositionTable struct {
+	slots      []ttSlot
+	mask       uint64
+	generation uint32
+	locks      [ttLocks]sync.Mutex
+}
+
+// NewTranspositionTable returns a TranspositionTable which has at most size entries.
+// size is rounded down to a power of 2.
+func NewTranspositionTable(size int) *TranspositionTable {
+	n := 1
+	for n*2 <= size {
+		n *= 2
+	}
+
+	return &TranspositionTable{slots: make([]ttSlot, n), mask: uint64(n - 1)}
+}
+
+// Len returns the number of slots
+func (t *TranspositionTable) Len() int {
+	return len(t.slots)
+}
+
+// NewSearch ages the entries so that the next search replaces them first
+func (t *TranspositionTable) NewSearch() {
+	atomic.AddUint32(&t.generation, 1)
+}
+
+// Clear removes all entries
+func (t *TranspositionTable) Clear() {
+	for i := range t.locks {
+		t.locks[i].Lock()
+	}
+
+	for i := range t.slots {
+		t.slots[i] = ttSlot{}
+	}
+
+	for i := range t.locks {
+		t.locks[i].Unlock()
+	}
+}
+
+// Probe returns the entry of the position of the key
+func (t *TranspositionTable) Probe(key uint64) (TTEntry, bool) {
+	i := key & t.mask
+	l := &t.locks[i%ttLocks]
+
+	l.Lock()
+	s := t.slots[i]
+	l.Unlock()
+
+	if !s.used || s.entry.Key != key {
+		return TTEntry{}, false
+	}
+
+	return s.entry, true
+}
+
+// Store saves e by the replacement policy
+func (t *TranspositionTable) Store(e TTEntry) {
+	i := e.Key & t.mask
+	l := &t.locks[i%ttLocks]
+	generation := atomic.LoadUint32(&t.generation)
+
+	l.Lock()
+	defer l.Unlock()
+
+	s := &t.slots[i]
+
+	if s.used && s.entry.Key != e.Key && s.generation == generation && s.entry.Depth > e.Depth {
+		return
+	}
+
+	*s = ttSlot{entry: e, generation: generation, used: true}
+}
@@ -0,0 +1,136 @@
+package mrsoft
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoRedo is returned by redo when no move has been undone
+var ErrNoRedo = errors.New("There is no move to redo")
+
+// Variation is a node of the variation tree.
+// Children are the moves tried after Move, the root has no Move.
+type Variation struct {
+	Move     Point        `json:"move"`
+	Children []*Variation `json:"children,omitempty"`
+	parent   *Variation
+	// index of the child to redo
+	redo int
+}
+
+// child returns the child of the move p, which is added unless it exists
+func (v *Variation) child(p Point) *Variation {
+	for i, c := range v.Children {
+		if c.Move.equal(p) {
+			v.redo = i
+			return c
+		}
+	}
+
+	c := &Variation{Move: p, parent: v}
+	v.redo = len(v.Children)
+	v.Children = append(v.Children, c)
+
+	return c
+}
+
+// branched reports whether any move has more than one child
+func (v *Variation) branched() bool {
+	if len(v.Children) > 1 {
+		return true
+	}
+
+	for _, c := range v.Children {
+		if c.branched() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lines returns the move sequences from v to every leaf
+func (v *Variation) Lines() (lines [][]Point) {
+	for _, c := range v.Children {
+		sub := c.Lines()
+
+		if len(sub) == 0 {
+			sub = [][]Point{nil}
+		}
+
+		for _, l := range sub {
+			lines = append(lines, append([]Point{c.Move}, l...))
+		}
+	}
+
+	return
+}
+
+// redo puts the move undone last again
+func (g *Game) redo() (err error) {
+	if len(g.node.Children) == 0 {
+		return ErrNoRedo
+	}
+
+	p := g.node.Children[g.node.redo].Move
+
+	if p.equal(passPoint) {
+		fmt.Println("REDO PASS")
+		g.pass()
+		return
+	}
+
+	fmt.Printf("REDO (%d, %d)\n", p[0], p[1])
+
+	if err = g.place(p, g.crr.color()); err != nil {
+		return
+	}
+
+	if err = g.put(p); err != nil {
+		return
+	}
+
+	g.crr = g.crr.enemy()
+
+	return
+}
+
+// replayForward redoes the last move and the replies of Engines
+func (g *Game) replayForward() (err error) {
+	for {
+		g.setAvailable()
+
+		if err = g.redo(); err != nil {
+			return
+		}
+
+		if len(g.node.Children) == 0 {
+			return
+		}
+
+		// a pass is forced, so redo the move after it as well
+		if g.node.Move.equal(passPoint) {
+			continue
+		}
+
+		if !g.hasHuman() || g.engines[g.crr] == nil {
+			return
+		}
+	}
+}
+
+// printVariations prints the lines other than the main line
+func (g *Game) printVariations() {
+	if !g.tree.branched() {
+		return
+	}
+
+	main := FormatMoves(g.moves())
+
+	fmt.Printf("\n# VARIATIONS\n")
+	for _, l := range g.tree.Lines() {
+		if s := FormatMoves(l); s != main {
+			fmt.Println(s)
+		}
+	}
+}
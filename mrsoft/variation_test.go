@@ -0,0 +1,64 @@
+package mrsoft
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestVariation(t *testing.T) {
+	m := &dammyMiddleware{
+		r: [][2]int{
+			{3, 4},
+			{3, 3},
+			// undo and redo c3
+			{-1, -1},
+			{-4, -4},
+			{3, 3},
+			// try e3 instead of c3
+			{-1, -1},
+			{5, 3},
+		},
+	}
+
+	g, err := NewGame(m)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = g.redo(); err != ErrNoRedo {
+		t.Fatalf("redo returns %v without undo", err)
+	}
+
+	if err = g.Start(); err == nil {
+		t.Fatal("Start finishes before the end of input")
+	}
+
+	if s := FormatMoves(g.moves()); s != "c4e3" {
+		t.Fatalf("moves are %s, want c4e3", s)
+	}
+
+	r := g.Record()
+
+	if r.Variations == nil {
+		t.Fatal("Record has no variation tree")
+	}
+
+	buf := &bytes.Buffer{}
+
+	if err = r.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := ReadRecord(buf)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := loaded.Lines()
+
+	if len(lines) != 2 || FormatMoves(lines[0]) != "c4c3" || FormatMoves(lines[1]) != "c4e3" {
+		t.Fatalf("variations are %v", lines)
+	}
+}
@@ -0,0 +1,83 @@
+package mrsoft
+
+// zobristKeys are random numbers of a black and a white stone on each cell
+var zobristKeys [MaxSize + 2][MaxSize + 2][2]uint64
+
+// zobristWhite is mixed into the hash when WHITE is to move
+var zobristWhite uint64
+
+// zobristSizes and zobristRules tell positions of other sizes and rules apart
+var (
+	zobristSizes [MaxSize + 1]uint64
+	zobristRules [2]uint64
+)
+
+func init() {
+	// splitmix64 with a fixed seed so that hashes are the same on every run
+	seed := uint64(0x4d616769635276)
+	next := func() uint64 {
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+
+	for y := range zobristKeys {
+		for x := range zobristKeys[y] {
+			zobristKeys[y][x] = [2]uint64{next(), next()}
+		}
+	}
+
+	zobristWhite = next()
+
+	for i := range zobristSizes {
+		zobristSizes[i] = next()
+	}
+
+	for i := range zobristRules {
+		zobristRules[i] = next()
+	}
+}
+
+// zobristStone returns the key of a stone of s on p, 0 for a blank cell
+func zobristStone(p Point, s State) uint64 {
+	switch s {
+	case BLACK:
+		return zobristKeys[p[1]][p[0]][0]
+	case WHITE:
+		return zobristKeys[p[1]][p[0]][1]
+	default:
+		return 0
+	}
+}
+
+// zobristFlip returns the difference of the hash when the stone on p is flipped
+func zobristFlip(p Point) uint64 {
+	return zobristKeys[p[1]][p[0]][0] ^ zobristKeys[p[1]][p[0]][1]
+}
+
+// zobrist returns the hash of all stones on the board
+func (b *board) zobrist() (h uint64) {
+	n := b.size()
+
+	for y := 1; y <= n; y++ {
+		for x := 1; x <= n; x++ {
+			h ^= zobristStone(Point{x, y}, b[y][x])
+		}
+	}
+
+	return
+}
+
+// Zobrist returns the Zobrist hash of pos, which is updated move by move.
+// Unlike Hash, symmetric positions have different hashes.
+func (pos *Position) Zobrist() uint64 {
+	h := pos.hash ^ zobristSizes[pos.b.size()] ^ zobristRules[pos.rules]
+
+	if pos.crr == WHITE {
+		h ^= zobristWhite
+	}
+
+	return h
+}
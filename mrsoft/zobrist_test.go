@@ -0,0 +1,178 @@
+package mrsoft
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestZobrist(t *testing.T) {
+	g, err := NewGame(&placingMiddleware{}, Size(6))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	seen := map[uint64]positionKey{}
+
+	check := func() {
+		pos := g.Position()
+
+		if g.hash != g.b.zobrist() {
+			t.Fatalf("hash of the Game is not updated at move %d", len(g.history))
+		}
+
+		if k, ok := seen[pos.Zobrist()]; ok && k != pos.key() {
+			t.Fatalf("hash collides at move %d", len(g.history))
+		}
+
+		seen[pos.Zobrist()] = pos.key()
+	}
+
+	for check(); !g.isFinish(); check() {
+		g.setAvailable()
+
+		if len(g.available) == 0 {
+			g.pass()
+			continue
+		}
+
+		pos := g.Position()
+		moves := pos.Moves()
+
+		if err = g.put(moves[r.Intn(len(moves))]); err != nil {
+			t.Fatal(err)
+		}
+
+		g.crr = g.crr.enemy()
+		g.setAvailable()
+	}
+
+	// Position follows the Game
+	pos, last := g.start, g.Position()
+	if err = pos.Replay(g.moves()); err != nil || pos.Zobrist() != last.Zobrist() {
+		t.Fatalf("hash of the replayed Position differs: %v", err)
+	}
+
+	for len(g.history) != 0 {
+		if err = g.undo(); err != nil {
+			t.Fatal(err)
+		}
+
+		check()
+	}
+
+	if last = g.Position(); last.Zobrist() != g.start.Zobrist() {
+		t.Fatal("hash differs after undoing all moves")
+	}
+
+	// the Player to move, the size and the rules make different hashes
+	a, _ := NewPosition(6)
+	b := a
+	b.crr = WHITE
+	c := a
+	c.rules = ANTI
+	d, _ := NewPosition(8)
+
+	if a.Zobrist() == b.Zobrist() || a.Zobrist() == c.Zobrist() || a.Zobrist() == d.Zobrist() {
+		t.Fatal("different positions have the same hash")
+	}
+}
+
+func TestTranspositionTable(t *testing.T) {
+	tt := NewTranspositionTable(1000)
+
+	if tt.Len() != 512 {
+		t.Fatalf("table has %d slots", tt.Len())
+	}
+
+	// keys of the same slot
+	deep := TTEntry{Key: 1, Score: 10, Depth: 5, Bound: ExactBound, Best: Point{3, 4}}
+	shallow := TTEntry{Key: 1 + 512, Score: -3, Depth: 2, Bound: LowerBound}
+
+	tt.Store(deep)
+	tt.Store(shallow)
+
+	if e, ok := tt.Probe(1); !ok || e != deep {
+		t.Fatalf("deeper entry is replaced: %+v", e)
+	}
+
+	if _, ok := tt.Probe(1 + 512); ok {
+		t.Fatal("shallower entry is stored")
+	}
+
+	// entries of old searches are replaced
+	tt.NewSearch()
+	tt.Store(shallow)
+
+	if e, ok := tt.Probe(1 + 512); !ok || e != shallow {
+		t.Fatalf("old entry is not replaced: %+v", e)
+	}
+
+	if _, ok := tt.Probe(1); ok {
+		t.Fatal("replaced entry is found")
+	}
+
+	tt.Clear()
+
+	if _, ok := tt.Probe(1 + 512); ok {
+		t.Fatal("entry is left after Clear")
+	}
+
+	// concurrent use
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < 10000; i++ {
+				key := uint64(i*8 + w)
+				tt.Store(TTEntry{Key: key, Score: i, Depth: i % 7})
+
+				if e, ok := tt.Probe(key); ok && e.Key != key {
+					t.Errorf("Probe(%d) returns the entry of %d", key, e.Key)
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+}
+
+func TestSearcherTable(t *testing.T) {
+	s := NewSearcher()
+	s.Depth = 4
+
+	withTable := NewSearcher()
+	withTable.Depth = 4
+	withTable.Table = NewTranspositionTable(1 << 16)
+
+	pos := InitialPosition()
+
+	for i := 0; i < 10 && !pos.IsFinish(); i++ {
+		a, err := s.Analyze(pos)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := withTable.Move(pos)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the table doesn't change the best score
+		for _, ms := range a {
+			if ms.Move.equal(m) && ms.Score != a[0].Score {
+				t.Fatalf("%s scores %d, but the best is %d", m.Notation(), ms.Score, a[0].Score)
+			}
+		}
+
+		pos.Replay([]Point{a[0].Move})
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net"
+	"os"
+
+	"github.com/69guitar1015/MagicReversi/mrnboard"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+	"github.com/sirupsen/logrus"
+)
+
+// serveNBoard serves the Game to NBoard GUIs on stdin and stdout if addr is "-",
+// or on the TCP address one connection after another
+func serveNBoard(g *mrsoft.Game, addr string) error {
+	s := mrnboard.NewServer(g)
+
+	if addr == "-" {
+		// messages of the Game go to stderr, leaving stdout to the protocol
+		proto := os.Stdout
+		os.Stdout = os.Stderr
+
+		return s.Serve(os.Stdin, proto)
+	}
+
+	l, err := net.Listen("tcp", addr)
+
+	if err != nil {
+		return err
+	}
+
+	defer l.Close()
+
+	for {
+		conn, err := l.Accept()
+
+		if err != nil {
+			return err
+		}
+
+		l := logger.WithFields(logrus.Fields{"component": "nboard", "remote": conn.RemoteAddr().String()})
+		l.Info("NBoard GUI is connected")
+
+		if err = s.Serve(conn, conn); err != nil {
+			l.WithError(err).Error("NBoard connection failed")
+		}
+
+		conn.Close()
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// runPerft runs the perft subcommand
+func runPerft(args []string) error {
+	fs := flag.NewFlagSet("perft", flag.ExitOnError)
+
+	depth := fs.Int("depth", 9, "depth of the game tree")
+	divide := fs.Bool("divide", false, "print the number of leaves after each root move at -depth")
+	generator := fs.String("generator", "bitboard", "move generator (seek, position or bitboard)")
+	start := fs.String("start", "standard 8", "root position as a board diagram or a transcript")
+
+	fs.Parse(args)
+
+	gen, err := mrsoft.ParseGenerator(*generator)
+
+	if err != nil {
+		return err
+	}
+
+	pos, err := mrsoft.ParsePosition(*start)
+
+	if err != nil {
+		return err
+	}
+
+	if *divide {
+		results, err := mrsoft.Divide(pos, *depth, gen)
+
+		if err != nil {
+			return err
+		}
+
+		var sum uint64
+
+		for _, r := range results {
+			fmt.Printf("%s\t%d\n", r.Move.Notation(), r.Nodes)
+			sum += r.Nodes
+		}
+
+		fmt.Printf("TOTAL\t%d\n", sum)
+
+		return nil
+	}
+
+	for d := 1; d <= *depth; d++ {
+		began := time.Now()
+		nodes, err := mrsoft.Perft(pos, d, gen)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%d\t%d\t%s\n", d, nodes, time.Since(began))
+	}
+
+	return nil
+}
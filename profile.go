@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/69guitar1015/MagicReversi/mrdb"
+	"github.com/69guitar1015/MagicReversi/mrsoft"
+)
+
+// gamePlayers are the names of the players of the Game
+type gamePlayers struct {
+	black, white string
+	// rated means both names are IDs of profiles
+	rated bool
+}
+
+// choosePlayers decides the players by flags, or by asking on the terminal
+// when profiles are registered
+func choosePlayers() (gp gamePlayers, err error) {
+	ps := &mrdb.Profiles{}
+
+	if *profiles != "" {
+		if ps, err = mrdb.OpenProfiles(*profiles); err != nil {
+			return
+		}
+	}
+
+	gp.rated = *dbPath != ""
+
+	for _, c := range []struct {
+		color string
+		name  *string
+		id    *string
+	}{
+		{"black", black, &gp.black},
+		{"white", white, &gp.white},
+	} {
+		name := *c.name
+
+		if name == "" && *cpu != c.color && len(ps.List()) != 0 {
+			name = choosePlayer(c.color, ps)
+		}
+
+		name = playerName(name, c.color)
+
+		if p, ok := ps.Get(name); ok {
+			*c.id = p.ID
+		} else {
+			*c.id, gp.rated = name, false
+		}
+	}
+
+	// a player can't be rated by a game against themselves
+	if strings.EqualFold(gp.black, gp.white) {
+		gp.rated = false
+	}
+
+	return
+}
+
+// choosePlayer asks the profile of the color, empty for a guest
+func choosePlayer(color string, ps *mrdb.Profiles) string {
+	list := ps.List()
+
+	for i, p := range list {
+		fmt.Printf("%2d\t%s\t%s\n", i+1, p.ID, p.Name)
+	}
+
+	for {
+		fmt.Printf("%s PLAYER (number or ID, empty for a guest): ", strings.ToUpper(color))
+
+		if !stdin.Scan() {
+			return ""
+		}
+
+		s := strings.TrimSpace(stdin.Text())
+
+		if s == "" {
+			return ""
+		}
+
+		if i, err := strconv.Atoi(s); err == nil && 1 <= i && i <= len(list) {
+			return list[i-1].ID
+		}
+
+		if p, ok := ps.Get(s); ok {
+			return p.ID
+		}
+
+		fmt.Printf("%s is not registered\n", s)
+	}
+}
+
+// dbRater stores the finished Game in the database and rates the players
+type dbRater struct {
+	path    string
+	players gamePlayers
+	// when the current Game started
+	started time.Time
+}
+
+// Observe keeps the time when each Game starts
+func (r *dbRater) Observe(e mrsoft.Event) {
+	if e.Kind == mrsoft.StartEvent {
+		r.started = e.Time
+	}
+}
+
+// Rate stores the record and returns the new ratings and the head-to-head record
+func (r *dbRater) Rate(rec *mrsoft.Record) (string, error) {
+	db, err := mrdb.Open(r.path)
+
+	if err != nil {
+		return "", err
+	}
+
+	black, white := r.players.black, r.players.white
+
+	g := &mrdb.Game{
+		Black:    black,
+		White:    white,
+		Started:  r.started,
+		Finished: time.Now(),
+		Record:   rec,
+		Rated:    r.players.rated,
+	}
+
+	if err = db.Add(g); err != nil {
+		return "", err
+	}
+
+	if g.Rating == nil {
+		return "", nil
+	}
+
+	h := db.HeadToHead(black, white)
+
+	return fmt.Sprintf("%s\t%.0f (%+.0f)\n%s\t%.0f (%+.0f)\n%s VS %s:\t%d WIN, %d DRAW, %d LOSS",
+		black, g.Rating.Black, g.Rating.Change,
+		white, g.Rating.White, -g.Rating.Change,
+		black, white, h.Wins, h.Draws, h.Losses), nil
+}